@@ -0,0 +1,25 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+)
+
+// shaders and the default icon are built into the binary so that it runs
+// from any directory
+//
+//go:embed *.glsl kitten.png
+var embedded embed.FS
+
+// assets is where shaders and bundled images are read from, replaced by a
+// directory on disk with -assets for live editing
+var assets fs.FS = embedded
+
+// diskFS opens files by their path on disk, absolute or relative to the
+// working directory, for files named by the user rather than bundled
+type diskFS struct{}
+
+func (diskFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
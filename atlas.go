@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-gl/mathgl/mgl32"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+)
+
+// atlasManifest is the JSON description of an atlas, giving the image
+// (relative to the manifest) and pixel rectangles of its named regions
+//
+//	{"image": "sprites.png", "regions": {"ship": {"x": 0, "y": 0, "w": 32, "h": 32}}}
+type atlasManifest struct {
+	Image   string `json:"image"`
+	Regions map[string]struct {
+		X, Y, W, H int
+	} `json:"regions"`
+}
+
+// parseAtlasManifest decodes the manifest read from the named file
+func parseAtlasManifest(data []byte, name string) (*atlasManifest, error) {
+	var m atlasManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %v: %v", name, err)
+	}
+	if m.Image == "" {
+		return nil, fmt.Errorf("atlas %v names no image", name)
+	}
+
+	return &m, nil
+}
+
+// uvs converts the regions to texture coordinates (u0, v0, u1, v1) on an
+// image of the given size, rejecting any that are empty or reach outside
+// it. Texture rows are uploaded top row first, so v grows downwards like
+// the pixel rectangles.
+func (m *atlasManifest) uvs(width, height int, name string) (map[string]mgl32.Vec4, error) {
+	uvs := make(map[string]mgl32.Vec4, len(m.Regions))
+	for region, r := range m.Regions {
+		if r.W <= 0 || r.H <= 0 {
+			return nil, fmt.Errorf("region %q of %v is %vx%v, not a whole pixel", region, name, r.W, r.H)
+		}
+		if r.X < 0 || r.Y < 0 || r.X+r.W > width || r.Y+r.H > height {
+			return nil, fmt.Errorf("region %q (%v,%v %vx%v) outside %vx%v atlas %v",
+				region, r.X, r.Y, r.W, r.H, width, height, name)
+		}
+
+		uvs[region] = mgl32.Vec4{
+			float32(r.X) / float32(width),
+			float32(r.Y) / float32(height),
+			float32(r.X+r.W) / float32(width),
+			float32(r.Y+r.H) / float32(height),
+		}
+	}
+
+	return uvs, nil
+}
+
+// Atlas is a single texture holding many named sprites
+type Atlas struct {
+	Texture *Texture
+	Width   int
+	Height  int
+
+	// the image file, for reloading the texture from
+	Image string
+
+	regions map[string]mgl32.Vec4
+}
+
+// LoadAtlas reads an atlas manifest and uploads its image. The image is
+// never flipped, whatever the options say, so that regions match the
+// sprite batch's top left origin.
+func LoadAtlas(manifest string, opts TextureOptions) (*Atlas, error) {
+	data, err := ioutil.ReadFile(manifest)
+	if err != nil {
+		return nil, err
+	}
+	m, err := parseAtlasManifest(data, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Atlas{Image: filepath.Join(filepath.Dir(manifest), m.Image)}
+	img, err := loadImage(diskFS{}, a.Image)
+	if err != nil {
+		return nil, err
+	}
+	a.Width, a.Height = img.Bounds().Dx(), img.Bounds().Dy()
+	if a.regions, err = m.uvs(a.Width, a.Height, manifest); err != nil {
+		return nil, err
+	}
+
+	opts.NoFlip = true
+	if a.Texture, err = NewTextureFromImage(img, opts); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Region returns the texture coordinates (u0, v0, u1, v1) of a named
+// sprite, ready to pass to SpriteBatch.Draw
+func (a *Atlas) Region(name string) (mgl32.Vec4, bool) {
+	uv, ok := a.regions[name]
+	return uv, ok
+}
+
+// Names lists the regions in alphabetical order
+func (a *Atlas) Names() []string {
+	names := make([]string, 0, len(a.regions))
+	for name := range a.regions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Delete frees the atlas's texture
+func (a *Atlas) Delete() {
+	a.Texture.Delete()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"testing"
+)
+
+func TestAtlasManifest(t *testing.T) {
+	m, err := parseAtlasManifest([]byte(`{
+		"image": "sprites.png",
+		"regions": {
+			"ship": {"x": 0, "y": 0, "w": 32, "h": 16},
+			"rock": {"x": 32, "y": 16, "w": 96, "h": 48}
+		}
+	}`), "sprites.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Image != "sprites.png" {
+		t.Errorf("image %q, want sprites.png", m.Image)
+	}
+
+	uvs, err := m.uvs(128, 64, "sprites.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]mgl32.Vec4{
+		"ship": {0.0, 0.0, 0.25, 0.25},
+		"rock": {0.25, 0.25, 1.0, 1.0},
+	} {
+		if uv := uvs[name]; uv != want {
+			t.Errorf("region %v has uv %v, want %v", name, uv, want)
+		}
+	}
+
+	for _, test := range []struct {
+		name     string
+		manifest string
+		err      string
+	}{
+		{"not json", `{"image": `, "failed to parse sprites.json: unexpected end of JSON input"},
+		{"no image", `{"regions": {}}`, "atlas sprites.json names no image"},
+		{
+			"past the right edge",
+			`{"image": "a.png", "regions": {"ship": {"x": 100, "y": 0, "w": 32, "h": 16}}}`,
+			`region "ship" (100,0 32x16) outside 128x64 atlas sprites.json`,
+		},
+		{
+			"past the bottom edge",
+			`{"image": "a.png", "regions": {"ship": {"x": 0, "y": 60, "w": 32, "h": 16}}}`,
+			`region "ship" (0,60 32x16) outside 128x64 atlas sprites.json`,
+		},
+		{
+			"before the start",
+			`{"image": "a.png", "regions": {"ship": {"x": -1, "y": 0, "w": 32, "h": 16}}}`,
+			`region "ship" (-1,0 32x16) outside 128x64 atlas sprites.json`,
+		},
+		{
+			"zero size",
+			`{"image": "a.png", "regions": {"dot": {"x": 4, "y": 4, "w": 0, "h": 8}}}`,
+			`region "dot" of sprites.json is 0x8, not a whole pixel`,
+		},
+	} {
+		m, err := parseAtlasManifest([]byte(test.manifest), "sprites.json")
+		if err == nil {
+			_, err = m.uvs(128, 64, "sprites.json")
+		}
+		if err == nil || err.Error() != test.err {
+			t.Errorf("%v: error %v, want %v", test.name, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"sync"
+	"time"
+)
+
+// audioWindow is the span of samples each level is measured over
+const audioWindow = 20 * time.Millisecond
+
+// audioMeter follows a decoded track in real time on its own goroutine,
+// keeping the RMS level of the most recent window for the render loop.
+// The track is analysed as though it were playing, but is not played.
+type audioMeter struct {
+	samples    []float32
+	sampleRate int
+
+	mu    sync.Mutex
+	level float32
+}
+
+// newAudioMeter decodes a 16-bit PCM WAV file and starts measuring it,
+// looping back to the start at the end of the track
+func newAudioMeter(file string) (*audioMeter, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	samples, rate, err := decodeWAV(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %v: %v", file, err)
+	}
+
+	m := &audioMeter{samples: samples, sampleRate: rate}
+	go m.run()
+
+	return m, nil
+}
+
+// Level is the latest RMS level in [0, 1]
+func (m *audioMeter) Level() float32 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.level
+}
+
+// run measures the window ending at the current playback position
+func (m *audioMeter) run() {
+	window := int(float64(m.sampleRate) * audioWindow.Seconds())
+	if window < 1 || len(m.samples) < window {
+		return
+	}
+
+	start := time.Now()
+	ticker := time.NewTicker(audioWindow / 2)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		pos := windowStart(int(time.Since(start).Seconds()*float64(m.sampleRate)), len(m.samples), window)
+		level := rms(m.samples[pos : pos+window])
+
+		m.mu.Lock()
+		m.level = level
+		m.mu.Unlock()
+	}
+}
+
+// windowStart is where a window of samples starts once played samples
+// have been, looping over a track of total samples. The window can start
+// anywhere up to its own length from the end, so a track exactly one
+// window long always measures all of it.
+func windowStart(played, total, window int) int {
+	return played % (total - window + 1)
+}
+
+// rms is the root mean square of samples
+func rms(samples []float32) float32 {
+	var sum float64
+	for _, s := range samples {
+		sum += float64(s) * float64(s)
+	}
+
+	return float32(math.Sqrt(sum / float64(len(samples))))
+}
+
+// decodeWAV extracts 16-bit PCM samples mixed down to mono
+func decodeWAV(data []byte) ([]float32, int, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, 0, fmt.Errorf("not a WAV file")
+	}
+
+	var channels, bits, rate int
+	for chunk := data[12:]; len(chunk) >= 8; {
+		id := string(chunk[0:4])
+		size := int(binary.LittleEndian.Uint32(chunk[4:8]))
+		if 8+size > len(chunk) {
+			size = len(chunk) - 8
+		}
+		body := chunk[8 : 8+size]
+
+		switch id {
+		case "fmt ":
+			if len(body) < 16 {
+				return nil, 0, fmt.Errorf("short fmt chunk")
+			}
+			if format := binary.LittleEndian.Uint16(body[0:2]); format != 1 {
+				return nil, 0, fmt.Errorf("unsupported encoding %v, need PCM", format)
+			}
+			channels = int(binary.LittleEndian.Uint16(body[2:4]))
+			rate = int(binary.LittleEndian.Uint32(body[4:8]))
+			bits = int(binary.LittleEndian.Uint16(body[14:16]))
+
+		case "data":
+			if channels == 0 {
+				return nil, 0, fmt.Errorf("data chunk before fmt chunk")
+			}
+			if bits != 16 {
+				return nil, 0, fmt.Errorf("unsupported %v-bit samples, need 16-bit", bits)
+			}
+
+			frames := len(body) / (2 * channels)
+			samples := make([]float32, frames)
+			for i := range samples {
+				var mix float32
+				for c := 0; c < channels; c++ {
+					off := 2 * (i*channels + c)
+					mix += float32(int16(binary.LittleEndian.Uint16(body[off:]))) / 32768.0
+				}
+				samples[i] = mix / float32(channels)
+			}
+
+			return samples, rate, nil
+		}
+
+		// chunks are padded to an even length
+		next := 8 + size + size%2
+		if next > len(chunk) {
+			break
+		}
+		chunk = chunk[next:]
+	}
+
+	return nil, 0, fmt.Errorf("no data chunk")
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// wavChunk is a RIFF chunk, padded to an even length
+func wavChunk(id string, body []byte) []byte {
+	chunk := append([]byte(id), 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(chunk[4:], uint32(len(body)))
+	chunk = append(chunk, body...)
+	if len(body)%2 != 0 {
+		chunk = append(chunk, 0)
+	}
+	return chunk
+}
+
+// wavFile wraps chunks in a RIFF WAVE header
+func wavFile(chunks ...[]byte) []byte {
+	var body []byte
+	for _, chunk := range chunks {
+		body = append(body, chunk...)
+	}
+
+	data := append([]byte("RIFF"), 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(data[4:], uint32(4+len(body)))
+	data = append(data, "WAVE"...)
+	return append(data, body...)
+}
+
+// wavFormat is a fmt chunk for the given encoding and layout
+func wavFormat(format, channels, rate, bits int) []byte {
+	body := make([]byte, 16)
+	binary.LittleEndian.PutUint16(body[0:], uint16(format))
+	binary.LittleEndian.PutUint16(body[2:], uint16(channels))
+	binary.LittleEndian.PutUint32(body[4:], uint32(rate))
+	binary.LittleEndian.PutUint32(body[8:], uint32(rate*channels*bits/8))
+	binary.LittleEndian.PutUint16(body[12:], uint16(channels*bits/8))
+	binary.LittleEndian.PutUint16(body[14:], uint16(bits))
+	return wavChunk("fmt ", body)
+}
+
+// wavSamples is a data chunk of interleaved 16-bit samples
+func wavSamples(samples ...int16) []byte {
+	body := make([]byte, 2*len(samples))
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(body[2*i:], uint16(s))
+	}
+	return wavChunk("data", body)
+}
+
+func TestDecodeWAV(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		data    []byte
+		samples []float32
+		rate    int
+	}{
+		{
+			"mono",
+			wavFile(wavFormat(1, 1, 8000, 16), wavSamples(0, 16384, -32768)),
+			[]float32{0.0, 0.5, -1.0},
+			8000,
+		},
+		{
+			"stereo is mixed down",
+			wavFile(wavFormat(1, 2, 44100, 16), wavSamples(16384, 0, -16384, -16384)),
+			[]float32{0.25, -0.5},
+			44100,
+		},
+		{
+			"odd-sized chunks are padded",
+			wavFile(wavFormat(1, 1, 8000, 16), wavChunk("note", []byte("odd")), wavSamples(8192)),
+			[]float32{0.25},
+			8000,
+		},
+		{
+			"truncated data keeps the whole frames",
+			// claims four frames but holds one and a half
+			append(wavFile(wavFormat(1, 2, 8000, 16)),
+				'd', 'a', 't', 'a', 16, 0, 0, 0, 0, 0x40, 0, 0x40, 0, 0x40),
+			[]float32{0.5},
+			8000,
+		},
+	} {
+		samples, rate, err := decodeWAV(test.data)
+		if err != nil {
+			t.Errorf("%v: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(samples, test.samples) || rate != test.rate {
+			t.Errorf("%v: %v at %v Hz, want %v at %v Hz", test.name, samples, rate, test.samples, test.rate)
+		}
+	}
+}
+
+func TestDecodeWAVErrors(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{"not RIFF", []byte("RIFX\x00\x00\x00\x00WAVE"), "not a WAV file"},
+		{"too short", []byte("RIFF"), "not a WAV file"},
+		{"no fmt chunk", wavFile(wavSamples(0, 0)), "data chunk before fmt chunk"},
+		{"fmt after data", wavFile(wavSamples(0, 0), wavFormat(1, 1, 8000, 16)), "data chunk before fmt chunk"},
+		{"short fmt", wavFile(wavChunk("fmt ", make([]byte, 8)), wavSamples(0)), "short fmt chunk"},
+		{"not PCM", wavFile(wavFormat(3, 1, 8000, 32), wavSamples(0, 0)), "unsupported encoding 3, need PCM"},
+		{"8-bit", wavFile(wavFormat(1, 1, 8000, 8), wavSamples(0)), "unsupported 8-bit samples, need 16-bit"},
+		{"no data chunk", wavFile(wavFormat(1, 1, 8000, 16)), "no data chunk"},
+	} {
+		if _, _, err := decodeWAV(test.data); err == nil || err.Error() != test.err {
+			t.Errorf("%v: error %v, want %v", test.name, err, test.err)
+		}
+	}
+}
+
+func TestAudioLevel(t *testing.T) {
+	// a full scale sine measures 1/sqrt(2) over whole periods, and a
+	// square wave its amplitude
+	sine := make([]float32, 800)
+	for i := range sine {
+		sine[i] = float32(math.Sin(2.0 * math.Pi * float64(i) / 100.0))
+	}
+	if level := rms(sine); math.Abs(float64(level)-math.Sqrt(0.5)) > 1e-5 {
+		t.Errorf("sine level %v, want %v", level, math.Sqrt(0.5))
+	}
+
+	square := []float32{0.25, -0.25, 0.25, -0.25}
+	if level := rms(square); level != 0.25 {
+		t.Errorf("square level %v, want 0.25", level)
+	}
+
+	// windows loop over the track, and one as long as the track always
+	// starts at its beginning
+	for _, test := range []struct{ played, total, window, want int }{
+		{0, 100, 10, 0},
+		{50, 100, 10, 50},
+		{91, 100, 10, 0},
+		{95, 100, 10, 4},
+		{12345, 10, 10, 0},
+	} {
+		if pos := windowStart(test.played, test.total, test.window); pos != test.want {
+			t.Errorf("window of %v after %v of %v starts at %v, want %v", test.window, test.played, test.total, pos, test.want)
+		}
+	}
+}
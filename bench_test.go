@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/angus-g/gopengl/geometry"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"os"
+	"runtime"
+	"testing"
+)
+
+// benchWindow is a hidden window whose context the mesh benchmarks draw
+// in, open only while benchmarks run
+var benchWindow *Window
+
+// sphere sizes the mesh benchmarks run over, in slices around
+var benchSlices = []int{8, 32, 128, 512}
+
+// TestMain opens the window the benchmarks need. Tests need no GL, so it
+// is only opened for go test -bench, leaving them to run without a
+// display. GLFW is driven from the main thread, which init locked.
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if bench := flag.Lookup("test.bench"); bench != nil && bench.Value.String() != "" {
+		window, err := NewWindow(64, 64, "benchmark", WindowOptions{Hidden: true})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "skipping mesh benchmarks: %v\n", err)
+		} else {
+			// each benchmark makes it current on its own goroutine
+			glfw.DetachCurrentContext()
+			benchWindow = window
+		}
+	}
+
+	code := m.Run()
+	if benchWindow != nil {
+		benchWindow.Destroy()
+	}
+	os.Exit(code)
+}
+
+// benchContext makes the benchmark window's context current for the
+// calling benchmark, with a program to upload and draw meshes with, and
+// returns a func releasing both
+func benchContext(b *testing.B) (*Program, func()) {
+	if benchWindow == nil {
+		b.Skip("no GL context")
+	}
+
+	runtime.LockOSThread()
+	benchWindow.Handle.MakeContextCurrent()
+	release := func() {
+		glfw.DetachCurrentContext()
+		runtime.UnlockOSThread()
+	}
+
+	program, err := NewProgram(assets, "vertex.glsl", "fragment.glsl")
+	if err != nil {
+		release()
+		b.Fatal(err)
+	}
+	program.Use()
+
+	return program, func() {
+		program.Delete()
+		release()
+	}
+}
+
+// benchSpheres runs fn as a sub-benchmark for each size of sphere, with
+// its allocations reported. Each run ends with gl.Finish, so it times the
+// GPU's share of the work and not just queuing it.
+func benchSpheres(b *testing.B, fn func(b *testing.B, program *Program, vertices []float32, indices []uint32)) {
+	for _, slices := range benchSlices {
+		vertices, indices := geometry.Sphere(slices/2, slices)
+		b.Run(fmt.Sprintf("vertices=%v", len(vertices)/geometry.VertexSize), func(b *testing.B) {
+			program, release := benchContext(b)
+			defer release()
+
+			b.ReportAllocs()
+			fn(b, program, vertices, indices)
+			gl.Finish()
+		})
+	}
+}
+
+func BenchmarkMeshUpload(b *testing.B) {
+	benchSpheres(b, func(b *testing.B, program *Program, vertices []float32, indices []uint32) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			NewMesh(program, vertices, indices, meshAttribs).Delete()
+		}
+	})
+}
+
+func BenchmarkMeshUpdate(b *testing.B) {
+	benchSpheres(b, func(b *testing.B, program *Program, vertices []float32, indices []uint32) {
+		mesh := NewDynamicMesh(program, vertices, indices, meshAttribs)
+		defer mesh.Delete()
+
+		// each update orphans storage a draw is still reading
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			mesh.Update(vertices)
+			mesh.Draw()
+		}
+	})
+}
+
+func BenchmarkMeshDraw(b *testing.B) {
+	benchSpheres(b, func(b *testing.B, program *Program, vertices []float32, indices []uint32) {
+		mesh := NewMesh(program, vertices, indices, meshAttribs)
+		defer mesh.Delete()
+
+		// set up the vertex array outside the timing
+		mesh.Draw()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			mesh.Draw()
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+)
+
+// bounds is an axis-aligned bounding box
+type bounds struct {
+	Min, Max mgl32.Vec3
+}
+
+// boundsOf finds the box enclosing the xyz positions at the start of
+// each stride floats
+func boundsOf(vertices []float32, stride int) bounds {
+	if len(vertices) < 3 {
+		return bounds{}
+	}
+
+	inf := float32(math.Inf(1))
+	b := bounds{
+		Min: mgl32.Vec3{inf, inf, inf},
+		Max: mgl32.Vec3{-inf, -inf, -inf},
+	}
+	for i := 0; i+2 < len(vertices); i += stride {
+		for j := 0; j < 3; j++ {
+			b.Min[j] = float32(math.Min(float64(b.Min[j]), float64(vertices[i+j])))
+			b.Max[j] = float32(math.Max(float64(b.Max[j]), float64(vertices[i+j])))
+		}
+	}
+
+	return b
+}
+
+// Center is the middle of the box
+func (b bounds) Center() mgl32.Vec3 {
+	return b.Min.Add(b.Max).Mul(0.5)
+}
+
+// Radius is that of the sphere around the box
+func (b bounds) Radius() float32 {
+	return b.Max.Sub(b.Min).Len() * 0.5
+}
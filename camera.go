@@ -0,0 +1,92 @@
+package main
+
+import (
+	"github.com/angus-g/gopengl/math3d"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Camera flies freely through the scene, turned by the mouse and moved
+// with WASD. Angles are in degrees, with yaw about the z axis and pitch
+// towards it, matching the scene's z up convention.
+type Camera struct {
+	Position mgl32.Vec3
+	Front    mgl32.Vec3
+	Up       mgl32.Vec3
+	Yaw      float32
+	Pitch    float32
+
+	// units per second and degrees per pixel of mouse movement
+	Speed       float32
+	Sensitivity float32
+
+	// cursor position at the last update, once there has been one
+	lastX, lastY float64
+	tracking     bool
+}
+
+// NewCamera places a camera at position looking towards target
+func NewCamera(position, target mgl32.Vec3) *Camera {
+	c := &Camera{
+		Position:    position,
+		Up:          mgl32.Vec3{0.0, 0.0, 1.0},
+		Speed:       2.0,
+		Sensitivity: 0.1,
+	}
+
+	front := target.Sub(position)
+	if front.Len() == 0.0 {
+		front = mgl32.Vec3{1.0, 0.0, 0.0}
+	}
+	c.Yaw, c.Pitch = math3d.YawPitch(front)
+	c.look()
+
+	return c
+}
+
+// ViewMatrix looks along the camera's front vector
+func (c *Camera) ViewMatrix() mgl32.Mat4 {
+	return mgl32.LookAtV(c.Position, c.Position.Add(c.Front), c.Up)
+}
+
+// Update turns the camera by the cursor movement since the last update
+// and moves it by the held WASD keys, scaled by the frame time in seconds
+func (c *Camera) Update(window *glfw.Window, dt float32) {
+	x, y := window.GetCursorPos()
+	if c.tracking {
+		// moving right turns clockwise seen from above, and screen y
+		// grows downwards
+		c.Yaw -= float32(x-c.lastX) * c.Sensitivity
+		c.Pitch -= float32(y-c.lastY) * c.Sensitivity
+	}
+	c.lastX, c.lastY, c.tracking = x, y, true
+
+	// keep short of straight up or down, where the view would flip
+	if c.Pitch > 89.0 {
+		c.Pitch = 89.0
+	} else if c.Pitch < -89.0 {
+		c.Pitch = -89.0
+	}
+	c.look()
+
+	right := c.Front.Cross(c.Up).Normalize()
+	step := c.Speed * dt
+
+	if window.GetKey(glfw.KeyW) == glfw.Press {
+		c.Position = c.Position.Add(c.Front.Mul(step))
+	}
+	if window.GetKey(glfw.KeyS) == glfw.Press {
+		c.Position = c.Position.Sub(c.Front.Mul(step))
+	}
+	if window.GetKey(glfw.KeyD) == glfw.Press {
+		c.Position = c.Position.Add(right.Mul(step))
+	}
+	if window.GetKey(glfw.KeyA) == glfw.Press {
+		c.Position = c.Position.Sub(right.Mul(step))
+	}
+}
+
+// look points the front vector by the yaw and pitch
+func (c *Camera) look() {
+	c.Front = math3d.Direction(c.Yaw, c.Pitch)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"time"
+)
+
+// frameCapture records the GL work of a single frame to a text file, as
+// a poor man's frame debugger. Draw code reports through logf, which does
+// nothing unless a capture is in progress.
+type frameCapture struct {
+	requested bool
+	file      *os.File
+	out       *bufio.Writer
+}
+
+// the capture shared by everything that draws, only used on the main thread
+var capture frameCapture
+
+// request captures the next frame
+func (c *frameCapture) request() {
+	c.requested = true
+}
+
+// begin starts recording at the top of a frame if one was requested
+func (c *frameCapture) begin() {
+	if !c.requested {
+		return
+	}
+	c.requested = false
+
+	name := time.Now().Format("frame-20060102-150405.txt")
+	file, err := os.Create(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start frame capture: %v\n", err)
+		return
+	}
+
+	c.file = file
+	c.out = bufio.NewWriter(file)
+	fmt.Fprintf(os.Stderr, "capturing frame to %v\n", name)
+}
+
+// logf records one operation of the frame being captured
+func (c *frameCapture) logf(format string, args ...interface{}) {
+	if c.out == nil {
+		return
+	}
+
+	fmt.Fprintf(c.out, format+"\n", args...)
+}
+
+// end finishes the recording at the bottom of a frame
+func (c *frameCapture) end() {
+	if c.out == nil {
+		return
+	}
+
+	if err := c.out.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write frame capture: %v\n", err)
+	}
+	c.file.Close()
+	c.file, c.out = nil, nil
+}
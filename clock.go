@@ -0,0 +1,26 @@
+package main
+
+// sceneClock is the time the scene has been animated for, kept apart
+// from the wall clock so that animation can be paused, reset or slowed
+type sceneClock struct {
+	Time      float32
+	TimeScale float32
+	Paused    bool
+}
+
+// Advance moves the clock on by a frame of dt wall clock seconds,
+// returning how much scene time passed
+func (c *sceneClock) Advance(dt float32) float32 {
+	if c.Paused {
+		return 0.0
+	}
+
+	dt *= c.TimeScale
+	c.Time += dt
+	return dt
+}
+
+// Reset winds the clock back to zero, leaving it paused or running
+func (c *sceneClock) Reset() {
+	c.Time = 0.0
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"github.com/go-gl/mathgl/mgl32"
+	"strconv"
+	"strings"
+)
+
+// clearPresets are cycled through at runtime
+var clearPresets = []mgl32.Vec3{
+	{1.0, 1.0, 1.0},
+	{0.0, 0.0, 0.0},
+	{0.5, 0.5, 0.5},
+}
+
+// colorValue is a flag.Value parsing an "R,G,B" triple of floats in [0, 1]
+type colorValue mgl32.Vec3
+
+func (c *colorValue) String() string {
+	return fmt.Sprintf("%g,%g,%g", c[0], c[1], c[2])
+}
+
+func (c *colorValue) Set(s string) error {
+	parts := strings.Split(s, ",")
+	if len(parts) != 3 {
+		return fmt.Errorf("expected R,G,B but got %q", s)
+	}
+
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return fmt.Errorf("bad color component %q", part)
+		}
+		if v < 0.0 || v > 1.0 {
+			return fmt.Errorf("color component %v outside [0, 1]", v)
+		}
+		c[i] = float32(v)
+	}
+
+	return nil
+}
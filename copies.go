@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// gridOffsets lays out n by n copies of a model in the xy plane, centred
+// on the origin, in units of the model's diameter
+func gridOffsets(n int) []mgl32.Vec3 {
+	const spacing = 1.25
+
+	offsets := make([]mgl32.Vec3, 0, n*n)
+	mid := float32(n-1) / 2.0
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			offsets = append(offsets, mgl32.Vec3{(float32(i) - mid) * spacing, (float32(j) - mid) * spacing, 0.0})
+		}
+	}
+
+	return offsets
+}
+
+// spread is the furthest any offset reaches from the origin
+func spread(offsets []mgl32.Vec3) float32 {
+	var furthest float32
+	for _, offset := range offsets {
+		if l := offset.Len(); l > furthest {
+			furthest = l
+		}
+	}
+
+	return furthest
+}
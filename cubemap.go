@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"image"
+	"image/draw"
+)
+
+// NewCubemap uploads six same-sized square images as the faces of a
+// GL_TEXTURE_CUBE_MAP, in GL's order of +X, -X, +Y, -Y, +Z, -Z. Faces are
+// seen from inside the cube with y up, as skyboxes are usually drawn, and
+// are not flipped since cubemaps put the origin at the top left of each
+// face. Faces hold sRGB colors if srgb is set, as in TextureOptions.
+func NewCubemap(faces [6]string, srgb bool) (*Texture, error) {
+	t := &Texture{target: gl.TEXTURE_CUBE_MAP, opts: TextureOptions{SRGB: srgb}}
+	gl.GenTextures(1, &t.ID)
+	gl.BindTexture(t.target, t.ID)
+
+	// clamp so the seams between faces do not pick up the opposite edge
+	gl.TexParameteri(t.target, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(t.target, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(t.target, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(t.target, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(t.target, gl.TEXTURE_WRAP_R, gl.CLAMP_TO_EDGE)
+
+	for i, file := range faces {
+		img, err := loadImage(diskFS{}, file)
+		if err != nil {
+			t.Delete()
+			return nil, err
+		}
+
+		size := img.Bounds().Size()
+		if size.X != size.Y {
+			t.Delete()
+			return nil, fmt.Errorf("cubemap face %v is %vx%v, faces must be square", file, size.X, size.Y)
+		}
+		if i > 0 && size.X != t.Width {
+			t.Delete()
+			return nil, fmt.Errorf("cubemap face %v is %vx%v but %v is %vx%v, faces must match",
+				file, size.X, size.Y, faces[0], t.Width, t.Height)
+		}
+		t.Width, t.Height = size.X, size.Y
+
+		rgba := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+		draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+
+		// the face targets follow each other in the same order as faces
+		gl.TexImage2D(gl.TEXTURE_CUBE_MAP_POSITIVE_X+uint32(i), 0, t.opts.internalFormat(),
+			int32(size.X), int32(size.Y),
+			0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
+	}
+
+	return t, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"os"
+	"unsafe"
+)
+
+// enableDebugOutput has the current context report errors and warnings
+// to stderr as they happen, reporting false if it lacks KHR_debug. The
+// context should be created with the debug hint, as drivers may say
+// nothing otherwise.
+func enableDebugOutput() bool {
+	if !hasExtension("GL_KHR_debug") {
+		return false
+	}
+
+	// synchronous output runs the callback inside the offending call, so
+	// it can be found from a stack trace
+	gl.Enable(gl.DEBUG_OUTPUT)
+	gl.Enable(gl.DEBUG_OUTPUT_SYNCHRONOUS)
+	gl.DebugMessageCallback(logDebugMessage, nil)
+
+	// notifications are mostly buffer placement chatter
+	gl.DebugMessageControl(gl.DONT_CARE, gl.DONT_CARE, gl.DEBUG_SEVERITY_NOTIFICATION, 0, nil, false)
+
+	return true
+}
+
+func logDebugMessage(source, gltype, id, severity uint32, length int32, message string, userParam unsafe.Pointer) {
+	fmt.Fprintf(os.Stderr, "gl %v %v (%v) from %v: %v\n", debugSeverity(severity), debugType(gltype), id, debugSource(source), message)
+}
+
+func debugSource(source uint32) string {
+	switch source {
+	case gl.DEBUG_SOURCE_API:
+		return "api"
+	case gl.DEBUG_SOURCE_WINDOW_SYSTEM:
+		return "window system"
+	case gl.DEBUG_SOURCE_SHADER_COMPILER:
+		return "shader compiler"
+	case gl.DEBUG_SOURCE_THIRD_PARTY:
+		return "third party"
+	case gl.DEBUG_SOURCE_APPLICATION:
+		return "application"
+	}
+	return "other"
+}
+
+func debugType(gltype uint32) string {
+	switch gltype {
+	case gl.DEBUG_TYPE_ERROR:
+		return "error"
+	case gl.DEBUG_TYPE_DEPRECATED_BEHAVIOR:
+		return "deprecated behaviour"
+	case gl.DEBUG_TYPE_UNDEFINED_BEHAVIOR:
+		return "undefined behaviour"
+	case gl.DEBUG_TYPE_PORTABILITY:
+		return "portability"
+	case gl.DEBUG_TYPE_PERFORMANCE:
+		return "performance"
+	case gl.DEBUG_TYPE_MARKER:
+		return "marker"
+	}
+	return "message"
+}
+
+func debugSeverity(severity uint32) string {
+	switch severity {
+	case gl.DEBUG_SEVERITY_HIGH:
+		return "high"
+	case gl.DEBUG_SEVERITY_MEDIUM:
+		return "medium"
+	case gl.DEBUG_SEVERITY_LOW:
+		return "low"
+	}
+	return "notification"
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+)
+
+// frameStats accumulates frame times, summarising them once a second
+type frameStats struct {
+	frames     int
+	total, max float64
+
+	// time of the last summary, or of the start
+	since float64
+}
+
+// add records a frame of dt seconds ending at now, both from
+// glfw.GetTime, and reports whether a second has passed since the last
+// summary
+func (s *frameStats) add(now, dt float64) bool {
+	s.frames++
+	s.total += dt
+	if dt > s.max {
+		s.max = dt
+	}
+
+	return now-s.since >= 1.0
+}
+
+// fps is the frame rate since the last summary
+func (s *frameStats) fps() float64 {
+	if s.total == 0.0 {
+		return 0.0
+	}
+	return float64(s.frames) / s.total
+}
+
+// String summarises the frame rate and frame times in milliseconds
+func (s *frameStats) String() string {
+	return fmt.Sprintf("%.0f fps, %.2f ms average, %.2f ms max",
+		s.fps(), 1000.0*s.total/float64(s.frames), 1000.0*s.max)
+}
+
+// reset starts the next summary at now
+func (s *frameStats) reset(now float64) {
+	*s = frameStats{since: now}
+}
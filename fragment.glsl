@@ -1,14 +1,53 @@
 #version 150
 
 in vec3 vertNorm;
+in vec3 fragPos;
+in vec2 fragTexCoord;
 
 uniform vec3 lightDir;
 uniform vec3 lightCol;
+uniform vec3 cameraPos;
+
+// share of the light reaching every surface, and tightness of highlights
+uniform float ambient;
+uniform float shininess;
+
+// the -texture image, whose alpha shows through when blending
+uniform sampler2D tex;
+uniform bool textured;
+
+// the -texture2 image, mixed over tex by mixFactor
+uniform sampler2D tex2;
+uniform bool mixing;
+uniform float mixFactor;
+
+// tint of the object being drawn, white to leave it as it is
+uniform vec3 materialColor;
 
 out vec4 outColor;
 
 void main() {
-    float brightness = max(dot(vertNorm, -normalize(lightDir)), 0.0);
+    vec3 N = normalize(vertNorm);
+    vec3 L = -normalize(lightDir);
+    vec3 V = normalize(cameraPos - fragPos);
+
+    float diffuse = max(dot(N, L), 0.0);
+
+    // no highlight on faces turned away from the light
+    float specular = 0.0;
+    if (diffuse > 0.0) {
+        specular = pow(max(dot(reflect(-L, N), V), 0.0), shininess);
+    }
+
+    vec4 base = vec4(1.0);
+    if (textured) {
+        base = texture(tex, fragTexCoord);
+    }
+    if (mixing) {
+        base = mix(base, texture(tex2, fragTexCoord), mixFactor);
+    }
+    base.rgb *= materialColor;
 
-    outColor = vec4(0.0) + brightness * vec4(lightCol, 1.0);
+    // highlights take the light's color, not the surface's
+    outColor = vec4(((ambient + diffuse) * base.rgb + specular) * lightCol, base.a);
 }
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// Framebuffer is an offscreen render target, with a color texture that
+// can be sampled once drawn and a combined depth and stencil buffer.
+// Framebuffer objects are not shared between windows, so it can only be
+// bound in the context it was created in. It is never multisampled.
+type Framebuffer struct {
+	Width, Height int
+
+	fbo   uint32
+	color uint32
+	depth uint32
+}
+
+// NewFramebuffer creates a framebuffer of the given size in pixels
+func NewFramebuffer(width, height int) (*Framebuffer, error) {
+	f := &Framebuffer{}
+	gl.GenFramebuffers(1, &f.fbo)
+	gl.GenTextures(1, &f.color)
+	gl.GenRenderbuffers(1, &f.depth)
+
+	if err := f.Resize(width, height); err != nil {
+		f.Delete()
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Resize reallocates the attachments at a new size, discarding their
+// contents
+func (f *Framebuffer) Resize(width, height int) error {
+	f.Width, f.Height = width, height
+
+	// one texel per pixel, so there is nothing to mipmap
+	gl.BindTexture(gl.TEXTURE_2D, f.color)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, int32(width), int32(height), 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+
+	gl.BindRenderbuffer(gl.RENDERBUFFER, f.depth)
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, int32(width), int32(height))
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, f.fbo)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, f.color, 0)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, f.depth)
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		return fmt.Errorf("framebuffer %vx%v is incomplete: status 0x%x", width, height, status)
+	}
+	return nil
+}
+
+// Bind directs drawing into the framebuffer, covering it with the
+// viewport
+func (f *Framebuffer) Bind() {
+	if f.fbo == 0 {
+		panic("Framebuffer.Bind called after Delete")
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, f.fbo)
+	gl.Viewport(0, 0, int32(f.Width), int32(f.Height))
+	capture.logf("framebuffer %v: %vx%v", f.fbo, f.Width, f.Height)
+}
+
+// Unbind directs drawing back to the window, leaving the viewport for
+// the caller to restore
+func (f *Framebuffer) Unbind() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+}
+
+// ColorTexture is the texture holding what was drawn
+func (f *Framebuffer) ColorTexture() uint32 {
+	return f.color
+}
+
+// Delete frees the framebuffer and its attachments, zeroing them so
+// that a second Delete panics
+func (f *Framebuffer) Delete() {
+	if f.fbo == 0 {
+		panic("Framebuffer.Delete called twice")
+	}
+	gl.DeleteFramebuffers(1, &f.fbo)
+	gl.DeleteTextures(1, &f.color)
+	gl.DeleteRenderbuffers(1, &f.depth)
+	f.fbo, f.color, f.depth = 0, 0, 0
+}
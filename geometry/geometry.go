@@ -0,0 +1,108 @@
+// Package geometry generates simple shapes as interleaved triangle data,
+// eight floats per vertex: position, normal and texture coordinate. All
+// shapes fit in [-1, 1] on each axis, with z up, and are wound
+// counter-clockwise seen from outside.
+package geometry
+
+import (
+	"math"
+)
+
+// VertexSize is the number of floats per vertex
+const VertexSize = 3 + 3 + 2
+
+// Cube is a 2x2x2 cube as 36 unindexed vertices, with the whole texture
+// on each face
+func Cube() []float32 {
+	// each face's normal with two axes across it, in the order that
+	// makes their cross product the normal
+	faces := [6][3][3]float32{
+		{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
+		{{-1, 0, 0}, {0, 0, 1}, {0, 1, 0}},
+		{{0, 1, 0}, {0, 0, 1}, {1, 0, 0}},
+		{{0, -1, 0}, {1, 0, 0}, {0, 0, 1}},
+		{{0, 0, 1}, {1, 0, 0}, {0, 1, 0}},
+		{{0, 0, -1}, {0, 1, 0}, {1, 0, 0}},
+	}
+
+	vertices := make([]float32, 0, 36*VertexSize)
+	for _, face := range faces {
+		n, u, v := face[0], face[1], face[2]
+		corner := func(s, t float32) {
+			for i := 0; i < 3; i++ {
+				vertices = append(vertices, n[i]+(2*s-1)*u[i]+(2*t-1)*v[i])
+			}
+			vertices = append(vertices, n[0], n[1], n[2], s, t)
+		}
+
+		corner(0, 0)
+		corner(1, 0)
+		corner(1, 1)
+		corner(0, 0)
+		corner(1, 1)
+		corner(0, 1)
+	}
+
+	return vertices
+}
+
+// Plane is a 2x2 square in the xy plane facing up the z axis, as six
+// unindexed vertices
+func Plane() []float32 {
+	return []float32{
+		-1, -1, 0, 0, 0, 1, 0, 0,
+		1, -1, 0, 0, 0, 1, 1, 0,
+		1, 1, 0, 0, 0, 1, 1, 1,
+		-1, -1, 0, 0, 0, 1, 0, 0,
+		1, 1, 0, 0, 0, 1, 1, 1,
+		-1, 1, 0, 0, 0, 1, 0, 1,
+	}
+}
+
+// Sphere is a unit sphere of stacks bands from pole to pole, each split
+// into slices around the z axis, with indices for drawing it. At least 2
+// stacks and 3 slices are used. The texture wraps once around, with a
+// seam of repeated vertices where u returns to 0.
+func Sphere(stacks, slices int) ([]float32, []uint32) {
+	if stacks < 2 {
+		stacks = 2
+	}
+	if slices < 3 {
+		slices = 3
+	}
+
+	vertices := make([]float32, 0, (stacks+1)*(slices+1)*VertexSize)
+	for i := 0; i <= stacks; i++ {
+		phi := math.Pi * float64(i) / float64(stacks)
+		for j := 0; j <= slices; j++ {
+			theta := 2.0 * math.Pi * float64(j) / float64(slices)
+
+			x := float32(math.Sin(phi) * math.Cos(theta))
+			y := float32(math.Sin(phi) * math.Sin(theta))
+			z := float32(math.Cos(phi))
+			u := float32(j) / float32(slices)
+			v := 1.0 - float32(i)/float32(stacks)
+
+			vertices = append(vertices, x, y, z, x, y, z, u, v)
+		}
+	}
+
+	// two triangles per quad, except at the poles where one of them
+	// would have no area
+	var indices []uint32
+	for i := 0; i < stacks; i++ {
+		for j := 0; j < slices; j++ {
+			a := uint32(i*(slices+1) + j)
+			b := a + uint32(slices+1)
+
+			if i > 0 {
+				indices = append(indices, a, b, a+1)
+			}
+			if i < stacks-1 {
+				indices = append(indices, a+1, b, b+1)
+			}
+		}
+	}
+
+	return vertices, indices
+}
@@ -0,0 +1,140 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+// tolerance for comparing results of float32 math
+const epsilon = 1e-5
+
+// checkVertices checks that every vertex lies in [-1, 1] with a unit
+// normal and texture coordinates in [0, 1]
+func checkVertices(t *testing.T, name string, vertices []float32) {
+	t.Helper()
+
+	if len(vertices)%VertexSize != 0 {
+		t.Fatalf("%v has %v floats, not a whole number of vertices", name, len(vertices))
+	}
+	for i := 0; i < len(vertices); i += VertexSize {
+		v := vertices[i : i+VertexSize]
+		for _, p := range v[0:3] {
+			if p < -1.0-epsilon || p > 1.0+epsilon {
+				t.Errorf("%v vertex %v at %v is outside [-1, 1]", name, i/VertexSize, v[0:3])
+				break
+			}
+		}
+		if n := length(v[3:6]); math.Abs(n-1.0) > epsilon {
+			t.Errorf("%v vertex %v has normal %v of length %v", name, i/VertexSize, v[3:6], n)
+		}
+		if v[6] < 0.0 || v[6] > 1.0 || v[7] < 0.0 || v[7] > 1.0 {
+			t.Errorf("%v vertex %v has texture coordinate %v outside [0, 1]", name, i/VertexSize, v[6:8])
+		}
+	}
+}
+
+// checkWinding checks that each triangle turns counter-clockwise seen
+// from the side its vertex normals face
+func checkWinding(t *testing.T, name string, vertices []float32, indices []uint32) {
+	t.Helper()
+
+	for i := 0; i+2 < len(indices); i += 3 {
+		a := vertices[indices[i]*VertexSize:]
+		b := vertices[indices[i+1]*VertexSize:]
+		c := vertices[indices[i+2]*VertexSize:]
+
+		var ab, ac [3]float64
+		for k := 0; k < 3; k++ {
+			ab[k] = float64(b[k] - a[k])
+			ac[k] = float64(c[k] - a[k])
+		}
+		face := [3]float64{
+			ab[1]*ac[2] - ab[2]*ac[1],
+			ab[2]*ac[0] - ab[0]*ac[2],
+			ab[0]*ac[1] - ab[1]*ac[0],
+		}
+
+		var facing float64
+		for k := 0; k < 3; k++ {
+			facing += face[k] * float64(a[3+k]+b[3+k]+c[3+k])
+		}
+		if facing <= 0.0 {
+			t.Errorf("%v triangle %v faces away from its normals", name, i/3)
+		}
+	}
+}
+
+// sequence is the indices of n unindexed vertices, in order
+func sequence(n int) []uint32 {
+	indices := make([]uint32, n)
+	for i := range indices {
+		indices[i] = uint32(i)
+	}
+	return indices
+}
+
+func length(v []float32) float64 {
+	return math.Sqrt(float64(v[0]*v[0] + v[1]*v[1] + v[2]*v[2]))
+}
+
+func TestCube(t *testing.T) {
+	vertices := Cube()
+	if n := len(vertices) / VertexSize; n != 36 {
+		t.Fatalf("cube has %v vertices, want 36", n)
+	}
+	checkVertices(t, "cube", vertices)
+	checkWinding(t, "cube", vertices, sequence(36))
+
+	// every corner is on the surface
+	for i := 0; i < len(vertices); i += VertexSize {
+		for _, p := range vertices[i : i+3] {
+			if math.Abs(float64(p)) != 1.0 {
+				t.Errorf("cube corner %v is not at a corner", vertices[i:i+3])
+				break
+			}
+		}
+	}
+}
+
+func TestPlane(t *testing.T) {
+	vertices := Plane()
+	if n := len(vertices) / VertexSize; n != 6 {
+		t.Fatalf("plane has %v vertices, want 6", n)
+	}
+	checkVertices(t, "plane", vertices)
+	checkWinding(t, "plane", vertices, sequence(6))
+}
+
+func TestSphere(t *testing.T) {
+	for _, size := range [][2]int{{2, 3}, {8, 16}, {31, 7}} {
+		stacks, slices := size[0], size[1]
+		vertices, indices := Sphere(stacks, slices)
+
+		if n, want := len(vertices)/VertexSize, (stacks+1)*(slices+1); n != want {
+			t.Errorf("%v by %v sphere has %v vertices, want %v", stacks, slices, n, want)
+		}
+		// two triangles per quad, less one at each pole
+		if n, want := len(indices), 3*slices*(2*stacks-2); n != want {
+			t.Errorf("%v by %v sphere has %v indices, want %v", stacks, slices, n, want)
+		}
+		for _, index := range indices {
+			if int(index) >= len(vertices)/VertexSize {
+				t.Fatalf("%v by %v sphere has index %v past its vertices", stacks, slices, index)
+			}
+		}
+
+		checkVertices(t, "sphere", vertices)
+		checkWinding(t, "sphere", vertices, indices)
+		for i := 0; i < len(vertices); i += VertexSize {
+			if r := length(vertices[i : i+3]); math.Abs(r-1.0) > epsilon {
+				t.Errorf("sphere vertex %v is %v from the center", vertices[i:i+3], r)
+			}
+		}
+	}
+
+	// too few stacks and slices are raised to the least that works
+	vertices, _ := Sphere(0, 0)
+	if n := len(vertices) / VertexSize; n != 3*4 {
+		t.Errorf("0 by 0 sphere has %v vertices, want those of 2 by 3", n)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"io"
+)
+
+// printGLState writes the render state of the current context, for
+// attaching to bug reports
+func printGLState(w io.Writer) {
+	integer := func(name uint32) int32 {
+		var v int32
+		gl.GetIntegerv(name, &v)
+		return v
+	}
+
+	fmt.Fprintf(w, "GL_VERSION:       %v\n", gl.GoStr(gl.GetString(gl.VERSION)))
+	fmt.Fprintf(w, "GL_RENDERER:      %v\n", gl.GoStr(gl.GetString(gl.RENDERER)))
+	fmt.Fprintf(w, "program:          %v\n", integer(gl.CURRENT_PROGRAM))
+	fmt.Fprintf(w, "vertex array:     %v\n", integer(gl.VERTEX_ARRAY_BINDING))
+	fmt.Fprintf(w, "array buffer:     %v\n", integer(gl.ARRAY_BUFFER_BINDING))
+	fmt.Fprintf(w, "active texture:   unit %v\n", integer(gl.ACTIVE_TEXTURE)-gl.TEXTURE0)
+	fmt.Fprintf(w, "texture 2D:       %v\n", integer(gl.TEXTURE_BINDING_2D))
+	fmt.Fprintf(w, "framebuffer:      %v\n", integer(gl.FRAMEBUFFER_BINDING))
+
+	var viewport [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	fmt.Fprintf(w, "viewport:         %v,%v %vx%v\n", viewport[0], viewport[1], viewport[2], viewport[3])
+
+	var clear [4]float32
+	gl.GetFloatv(gl.COLOR_CLEAR_VALUE, &clear[0])
+	fmt.Fprintf(w, "clear color:      %.3f,%.3f,%.3f,%.3f\n", clear[0], clear[1], clear[2], clear[3])
+
+	for _, c := range []struct {
+		name string
+		cap  uint32
+	}{
+		{"depth test", gl.DEPTH_TEST},
+		{"blend", gl.BLEND},
+		{"cull face", gl.CULL_FACE},
+		{"stencil test", gl.STENCIL_TEST},
+		{"scissor test", gl.SCISSOR_TEST},
+		{"multisample", gl.MULTISAMPLE},
+		{"polygon offset", gl.POLYGON_OFFSET_FILL},
+	} {
+		fmt.Fprintf(w, "%-18v%v\n", c.name+":", gl.IsEnabled(c.cap))
+	}
+
+	fmt.Fprintf(w, "depth func:       0x%x\n", integer(gl.DEPTH_FUNC))
+	fmt.Fprintf(w, "depth write:      %v\n", integer(gl.DEPTH_WRITEMASK) != gl.FALSE)
+}
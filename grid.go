@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// gridAttribs is the layout of grid lines, which only have positions
+var gridAttribs = []VertexAttrib{{"position", 3, 0}}
+
+// Grid builds lines spacing apart on the xy plane, size of them out from
+// the origin each way, for drawing a floor with gl.LINES
+func Grid(program *Program, size int, spacing float32) *Mesh {
+	extent := float32(size) * spacing
+
+	vertices := make([]float32, 0, (2*size+1)*2*2*3)
+	for i := -size; i <= size; i++ {
+		offset := float32(i) * spacing
+		vertices = append(vertices,
+			offset, -extent, 0.0, offset, extent, 0.0,
+			-extent, offset, 0.0, extent, offset, 0.0)
+	}
+
+	m := NewMesh(program, vertices, nil, gridAttribs)
+	m.Primitive = gl.LINES
+	return m
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"io"
+)
+
+// CaptureCursor hides the cursor and locks it to the window for mouse
+// look, using unaccelerated motion where the platform supports it
+func CaptureCursor(window *glfw.Window) {
+	window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
+	if glfw.RawMouseMotionSupported() {
+		window.SetInputMode(glfw.RawMouseMotion, glfw.True)
+	}
+}
+
+// ReleaseCursor returns the cursor to normal pointer behaviour
+func ReleaseCursor(window *glfw.Window) {
+	if glfw.RawMouseMotionSupported() {
+		window.SetInputMode(glfw.RawMouseMotion, glfw.False)
+	}
+	window.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+}
+
+// KeyHandler receives key events, returning true to consume the event so
+// that handlers registered after it never see it
+type KeyHandler func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool
+
+// MouseButtonHandler receives mouse button events, returning true to
+// consume them
+type MouseButtonHandler func(button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) bool
+
+// CursorPosHandler receives cursor movement in window coordinates,
+// returning true to consume it
+type CursorPosHandler func(x, y float64) bool
+
+// ScrollHandler receives scroll offsets, returning true to consume them
+type ScrollHandler func(xoff, yoff float64) bool
+
+// ResizeHandler receives the new framebuffer size in pixels, returning
+// true to consume it
+type ResizeHandler func(width, height int) bool
+
+// Input is registered once as a window's GLFW callbacks and fans each
+// event out to its handlers in registration order, so that independent
+// features can share a window without clobbering each other's callbacks
+type Input struct {
+	keys    []KeyHandler
+	buttons []MouseButtonHandler
+	cursors []CursorPosHandler
+	scrolls []ScrollHandler
+	resizes []ResizeHandler
+
+	bindings []keyBinding
+}
+
+// keyBinding describes a key handler for the help listing
+type keyBinding struct {
+	keys, help string
+}
+
+// dispatchers for each window, only touched from the main thread
+var inputs = map[*glfw.Window]*Input{}
+
+// InputFor returns the dispatcher of a window, installing it as the
+// window's callbacks the first time it is requested
+func InputFor(window *glfw.Window) *Input {
+	if in, ok := inputs[window]; ok {
+		return in
+	}
+
+	in := &Input{}
+	window.SetKeyCallback(func(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+		for _, fn := range in.keys {
+			if fn(key, scancode, action, mods) {
+				return
+			}
+		}
+	})
+	window.SetMouseButtonCallback(func(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+		for _, fn := range in.buttons {
+			if fn(button, action, mods) {
+				return
+			}
+		}
+	})
+	window.SetCursorPosCallback(func(w *glfw.Window, x, y float64) {
+		for _, fn := range in.cursors {
+			if fn(x, y) {
+				return
+			}
+		}
+	})
+	window.SetScrollCallback(func(w *glfw.Window, xoff, yoff float64) {
+		for _, fn := range in.scrolls {
+			if fn(xoff, yoff) {
+				return
+			}
+		}
+	})
+	window.SetFramebufferSizeCallback(func(w *glfw.Window, width, height int) {
+		for _, fn := range in.resizes {
+			if fn(width, height) {
+				return
+			}
+		}
+	})
+
+	inputs[window] = in
+	return in
+}
+
+// forgetInput drops the dispatcher of a window that is being destroyed
+func forgetInput(window *glfw.Window) {
+	delete(inputs, window)
+}
+
+// RegisterKeyHandler adds a handler for key events
+func (in *Input) RegisterKeyHandler(fn KeyHandler) {
+	in.keys = append(in.keys, fn)
+}
+
+// RegisterKeyBinding adds a key handler along with the keys it responds to
+// and what they do, so that it shows up in the help listing
+func (in *Input) RegisterKeyBinding(keys, help string, fn KeyHandler) {
+	in.bindings = append(in.bindings, keyBinding{keys, help})
+	in.RegisterKeyHandler(fn)
+}
+
+// PrintBindings lists the described key bindings in registration order
+func (in *Input) PrintBindings(w io.Writer) {
+	width := 0
+	for _, b := range in.bindings {
+		if len(b.keys) > width {
+			width = len(b.keys)
+		}
+	}
+	for _, b := range in.bindings {
+		fmt.Fprintf(w, "  %-*s  %v\n", width, b.keys, b.help)
+	}
+}
+
+// EnableEscapeToClose has Escape ask a window to close, ending a render
+// loop that checks ShouldClose. It goes through the window's dispatcher
+// like any other binding, so handlers registered before it can still
+// claim Escape for themselves.
+func EnableEscapeToClose(window *glfw.Window) {
+	InputFor(window).RegisterKeyBinding("Escape", "close the window", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if key != glfw.KeyEscape || action != glfw.Press {
+			return false
+		}
+
+		window.SetShouldClose(true)
+		return true
+	})
+}
+
+// RegisterMouseButtonHandler adds a handler for mouse button events
+func (in *Input) RegisterMouseButtonHandler(fn MouseButtonHandler) {
+	in.buttons = append(in.buttons, fn)
+}
+
+// RegisterCursorPosHandler adds a handler for cursor movement
+func (in *Input) RegisterCursorPosHandler(fn CursorPosHandler) {
+	in.cursors = append(in.cursors, fn)
+}
+
+// RegisterScrollHandler adds a handler for scroll events
+func (in *Input) RegisterScrollHandler(fn ScrollHandler) {
+	in.scrolls = append(in.scrolls, fn)
+}
+
+// RegisterResizeHandler adds a handler for framebuffer size changes
+func (in *Input) RegisterResizeHandler(fn ResizeHandler) {
+	in.resizes = append(in.resizes, fn)
+}
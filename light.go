@@ -0,0 +1,25 @@
+package main
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Light is a directional light shining along Direction, as used by the
+// Phong and PBR shaders
+type Light struct {
+	Direction mgl32.Vec3
+	Color     mgl32.Vec3
+
+	// Phong only: the share of the light reaching every surface, and the
+	// specular exponent, higher for tighter highlights
+	Ambient   float32
+	Shininess float32
+}
+
+// upload sets the light uniforms on the currently bound program
+func (l *Light) upload(program *Program) {
+	program.SetVec3("lightDir", l.Direction)
+	program.SetVec3("lightCol", l.Color)
+	program.SetFloat("ambient", l.Ambient)
+	program.SetFloat("shininess", l.Shininess)
+}
@@ -1,18 +1,25 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
-	"github.com/angus-g/go-obj/obj"
+	"github.com/angus-g/gopengl/math3d"
 	"github.com/go-gl/gl/v3.3-core/gl"
-	"github.com/go-gl/glfw/v3.1/glfw"
+	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
 	"image"
-	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
 	_ "image/png"
-	"io/ioutil"
+	"io/fs"
+	"log"
+	"math"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 )
 
 func init() {
@@ -20,147 +27,1201 @@ func init() {
 	runtime.LockOSThread()
 }
 
+// usageError is a mistake on the command line, reported along with the
+// usage and exit status 2 like the flag package's own
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
 func main() {
-	vertices, normals := obj.Parse(os.Args[1])
+	if err := run(); err != nil {
+		var usage usageError
+		if errors.As(err, &usage) {
+			fmt.Fprintln(os.Stderr, usage)
+			flag.Usage()
+			os.Exit(2)
+		}
+		log.Fatal(err)
+	}
+}
+
+// run shows the scene until the main window is closed, cleaning up after
+// itself on any failure
+func run() error {
+	width := flag.Int("width", 640, "window width in screen coordinates")
+	height := flag.Int("height", 480, "window height in screen coordinates")
+	title := flag.String("title", "GOpenGL", "window title")
+	vert := flag.String("vert", "vertex.glsl", "vertex shader, from the bundled or -assets shaders")
+	frag := flag.String("frag", "", "fragment shader, from the bundled or -assets shaders, instead of the Phong or PBR one")
+	texture := flag.String("texture", "", "image bound to the scene shader's tex sampler")
+	splat := flag.String("splat", "", "comma-separated images of a texture array, blended by height over a floor of hills, lowest ground first")
+	texture2 := flag.String("texture2", "", "image bound to the tex2 sampler, mixed over -texture")
+	pbr := flag.Bool("pbr", false, "use physically based shading instead of Phong")
+	metallic := flag.Float64("metallic", 0.0, "PBR metalness in [0, 1]")
+	roughness := flag.Float64("roughness", 0.5, "PBR roughness in [0, 1]")
+	icon := flag.String("icon", "", "comma-separated window icon images, in several sizes, instead of the bundled kitten")
+	clearColor := colorValue(clearPresets[0])
+	flag.Var(&clearColor, "clear", "background color as R,G,B in [0, 1]")
+	hudFont := flag.String("font", "", "bitmap font atlas of 16 by 6 ASCII glyphs from the space, for a text overlay of the frame rate and camera position")
+	sprite := flag.String("sprite", "", "image to overlay in the corner of the main window, or a .json atlas manifest whose regions are drawn in a row")
+	fov := flag.Float64("fov", 45.0, "vertical field of view in degrees")
+	near := flag.Float64("near", 1.0, "distance to the near clipping plane")
+	far := flag.Float64("far", 10.0, "distance to the far clipping plane")
+	reverseZ := flag.Bool("reversez", false, "use reversed-z depth, or logarithmic depth if clip control is unavailable")
+	audio := flag.String("audio", "", "16-bit PCM WAV file driving the audioLevel uniform")
+	vsync := flag.Bool("vsync", true, "wait for the display before each frame; some drivers leave the frame rate fully uncapped without it")
+	fpsCap := flag.Int("fpscap", 0, "limit the frame rate without vsync, 0 for uncapped")
+	wireColor := colorValue{0.0, 0.0, 0.0}
+	tint := colorValue{1.0, 1.0, 1.0}
+	flag.Var(&tint, "tint", "color multiplied into the model's as R,G,B in [0, 1]")
+	flag.Var(&wireColor, "wirecolor", "color of edges drawn over the model as R,G,B in [0, 1]")
+	inspector := flag.Bool("inspector", false, "open a second window viewing the scene from above")
+	timeScale := flag.Float64("timescale", 1.0, "speed of the animation relative to real time")
+	srgb := flag.Bool("srgb", false, "draw in linear color to an sRGB framebuffer, decoding color textures to linear")
+	debug := flag.Bool("debug", false, "log OpenGL errors and warnings as they happen")
+	msaa := flag.Int("msaa", 4, "multisampling samples per pixel, 0 to disable")
+	recordPath := flag.String("record", "", "record the main window to a looping GIF at this path")
+	recordSeconds := flag.Float64("record-seconds", 3.0, "length of the -record animation in seconds")
+	recordExit := flag.Bool("record-exit", false, "quit once the -record animation is written")
+	skyFaces := flag.String("skybox", "", "six comma-separated cubemap images to draw behind the scene, ordered +X,-X,+Y,-Y,+Z,-Z with y up")
+	drawOffscreen := flag.Bool("offscreen", false, "draw the main window's scene to a texture first and copy it to the window")
+	cull := flag.Bool("cull", true, "skip back faces, which only closed models can do without")
+	blend := flag.Bool("blend", false, "blend the model by the alpha of its texture")
+	depthWrite := flag.Bool("depthwrite", true, "write depth when drawing the model, off to see through blended models")
+	gridSize := flag.Int("grid", 0, "draw a floor grid under the model with this many lines out from the middle each way")
+	gridSpacing := flag.Float64("gridspacing", 0.5, "distance between floor grid lines")
+	gridColor := colorValue{0.5, 0.5, 0.5}
+	flag.Var(&gridColor, "gridcolor", "color of the floor grid as R,G,B in [0, 1]")
+	showPath := flag.Bool("path", false, "leave a tube along the path flown with C")
+	wave := flag.Bool("wave", false, "show a rippling sheet, updated every frame, in place of a model")
+	copies := flag.Int("copies", 1, "draw an n by n grid of copies of the model")
+	showFPS := flag.Bool("fps", false, "print the frame rate and frame times every second, and show the frame rate in the title")
+	assetDir := flag.String("assets", "", "directory to read shaders from instead of the bundled copies, rebuilding them when they change")
+	flag.Parse()
 
-	// initialize GLFW
-	if err := glfw.Init(); err != nil {
-		panic(err)
+	if *assetDir != "" {
+		assets = os.DirFS(*assetDir)
 	}
-	defer glfw.Terminate()
 
-	// set opengl core profile 3.3
-	glfw.WindowHint(glfw.ContextVersionMajor, 3)
-	glfw.WindowHint(glfw.ContextVersionMinor, 3)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+	if *msaa < 0 {
+		return usageError("multisampling samples must not be negative")
+	}
+
+	if *recordSeconds <= 0.0 {
+		return usageError("recording must last some time")
+	}
 
-	window, err := glfw.CreateWindow(640, 480, "GOpenGL", nil, nil)
+	if *gridSize < 0 || *gridSpacing <= 0.0 {
+		return usageError("grid size must not be negative and its spacing must be positive")
+	}
+
+	if *copies < 1 {
+		return usageError("need at least one copy of the model")
+	}
+
+	if *width <= 0 || *height <= 0 {
+		return usageError("window size must be positive")
+	}
+
+	if *fpsCap < 0 {
+		return usageError("frame rate cap must not be negative")
+	}
+
+	proj := projection{FOV: float32(*fov), Near: float32(*near), Far: float32(*far)}
+	if err := proj.validate(); err != nil {
+		return usageError(err.Error())
+	}
+
+	library, err := newModelLibrary(flag.Arg(0))
+	if err != nil {
+		return err
+	}
+	shown, err := library.Model()
 	if err != nil {
-		panic(err)
+		return err
 	}
-	window.MakeContextCurrent()
 
-	// initialise OpenGL library
-	if err := gl.Init(); err != nil {
-		panic(err)
+	// the wave's vertices are replaced every frame, keeping its indices
+	const waveSquares = 64
+	if *wave {
+		shown = waveSurface(waveSquares, 0.0)
 	}
 
-	// link program from shaders
-	program, err := newProgram("vertex.glsl", "fragment.glsl")
+	// window icons are cosmetic, so carry on without them
+	icons, err := loadIcons(assets, "kitten.png")
+	if *icon != "" {
+		icons, err = loadIcons(diskFS{}, *icon)
+	}
 	if err != nil {
-		panic(err)
+		fmt.Fprintf(os.Stderr, "failed to load icon: %v\n", err)
 	}
-	gl.UseProgram(program)
 
-	// vertex attribute object holds links between attributes and vbo
-	var vao uint32
-	gl.GenVertexArrays(1, &vao)
-	gl.BindVertexArray(vao)
+	window, err := NewWindow(*width, *height, *title, WindowOptions{Samples: *msaa, Debug: *debug, SRGB: *srgb})
+	if err != nil {
+		return err
+	}
+	defer window.Destroy()
+	if window.Samples < *msaa {
+		fmt.Fprintf(os.Stderr, "asked for %vx multisampling but got %vx\n", *msaa, window.Samples)
+	}
 
-	// vertex buffer with per-vertex data
-	var vbo [2]uint32
-	gl.GenBuffers(2, &vbo[0])
+	// with vsync the frame rate reads as the refresh rate, so -fps only
+	// measures the renderer with -vsync=false
+	if *vsync {
+		glfw.SwapInterval(1)
+	} else {
+		glfw.SwapInterval(0)
+	}
+	if *showFPS && *vsync {
+		fmt.Fprintln(os.Stderr, "frame rate is limited by vsync, use -vsync=false to measure it uncapped")
+	}
 
-	// position data
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo[0])
-	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STATIC_DRAW)
+	if *debug && !enableDebugOutput() {
+		fmt.Fprintln(os.Stderr, "no KHR_debug, OpenGL errors will not be logged")
+	}
 
-	// set up position attribute with layout of vertices
-	posAttrib := uint32(gl.GetAttribLocation(program, gl.Str("position\x00")))
-	gl.VertexAttribPointer(posAttrib, 3, gl.FLOAT, false, 3*4, gl.PtrOffset(0))
-	gl.EnableVertexAttribArray(posAttrib)
+	primary := newView(window.Handle, *title, mgl32.Vec3{2.0, 2.0, 2.0})
+	primary.window.SetIcon(icons)
 
-	// normal data
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo[1])
-	gl.BufferData(gl.ARRAY_BUFFER, len(normals)*4, gl.Ptr(normals), gl.STATIC_DRAW)
+	// reversed-z needs clip control, which is core only from 4.5
+	if *reverseZ {
+		if hasExtension("GL_ARB_clip_control") {
+			proj.Depth = depthReversed
+		} else {
+			fmt.Fprintln(os.Stderr, "no clip control, falling back to logarithmic depth")
+			proj.Depth = depthLogarithmic
+		}
+	}
 
-	normAttrib := uint32(gl.GetAttribLocation(program, gl.Str("normal\x00")))
-	gl.VertexAttribPointer(normAttrib, 3, gl.FLOAT, false, 3*4, gl.PtrOffset(0))
-	gl.EnableVertexAttribArray(normAttrib)
+	// link program from shaders
+	fragmentShader := *frag
+	if fragmentShader == "" && *pbr {
+		fragmentShader = "pbr.glsl"
+	} else if fragmentShader == "" {
+		fragmentShader = "fragment.glsl"
+	}
+	program, err := NewProgram(assets, *vert, fragmentShader)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// the program may have been replaced from the clipboard
+		program.Delete()
+	}()
+	program.Use()
 
-	uniModel := gl.GetUniformLocation(program, gl.Str("model\x00"))
-	uniView := gl.GetUniformLocation(program, gl.Str("view\x00"))
-	uniProj := gl.GetUniformLocation(program, gl.Str("proj\x00"))
+	// the shown model is uploaded again when it changes, flattened into
+	// one normal per face on request, and shared between all views
+	flat := false
+	var mesh *Mesh
+	show := func(m *modelData) {
+		if mesh != nil {
+			mesh.Delete()
+		}
+		if flat {
+			m = flatShaded(m)
+		}
+		if *wave {
+			mesh = NewDynamicMesh(program, m.vertices, m.indices, meshAttribs)
+		} else {
+			mesh = NewMesh(program, m.vertices, m.indices, meshAttribs)
+		}
+	}
+	show(shown)
+	defer func() {
+		mesh.Delete()
+	}()
 
-	matView := mgl32.LookAt(2.0, 2.0, 2.0,
-		0.0, 0.0, 0.0,
-		0.0, 0.0, 1.0)
-	gl.UniformMatrix4fv(uniView, 1, false, &matView[0])
+	proj.apply()
+	views := []*view{primary}
+	defer func() {
+		for _, v := range views[1:] {
+			v.destroy()
+		}
 
-	matProj := mgl32.Perspective(mgl32.DegToRad(45.0), 640.0/480.0, 1.0, 10.0)
-	gl.UniformMatrix4fv(uniProj, 1, false, &matProj[0])
+		// leave the shared objects to be deleted in the main context
+		primary.window.MakeContextCurrent()
+	}()
 
-	uniLightDir := gl.GetUniformLocation(program, gl.Str("lightDir\x00"))
-	uniLightCol := gl.GetUniformLocation(program, gl.Str("lightCol\x00"))
+	// a second window looking at the scene from above
+	if *inspector {
+		shared, err := glfw.CreateWindow(*width, *height, *title+" inspector", nil, primary.window)
+		if err != nil {
+			return err
+		}
+		inspect := newView(shared, *title+" inspector", mgl32.Vec3{0.0, 0.1, 5.0})
+		inspect.window.SetIcon(icons)
+		inspect.window.MakeContextCurrent()
+		proj.apply()
 
-	gl.Uniform3f(uniLightDir, -0.5, 0.0, -1.0)
-	gl.Uniform3f(uniLightCol, 0.0, 0.5, 0.5)
+		// only the primary waits for the display, or every extra window
+		// would divide the frame rate again
+		glfw.SwapInterval(0)
+		if *debug {
+			enableDebugOutput()
+		}
+		if *srgb {
+			gl.Enable(gl.FRAMEBUFFER_SRGB)
+		}
+		views = append(views, inspect)
+	}
 
-	startTime := glfw.GetTime()
-	gl.Enable(gl.DEPTH_TEST)
-	gl.ClearColor(1.0, 1.0, 1.0, 1.0)
+	// optional textures for the scene shader, on the first two units,
+	// reloaded when their files change
+	var sceneTexture, mixTexture *Texture
+	var textureWatches []*textureWatch
+	if *texture != "" {
+		if sceneTexture, err = NewTexture(diskFS{}, *texture, TextureOptions{Mipmaps: true, SRGB: *srgb}); err != nil {
+			return err
+		}
+		defer sceneTexture.Delete()
+		textureWatches = append(textureWatches, watchTexture(sceneTexture, *texture))
+	}
+	if *texture2 != "" {
+		if sceneTexture == nil {
+			return fmt.Errorf("-texture2 is mixed over -texture, which is not set")
+		}
+		if mixTexture, err = NewTexture(diskFS{}, *texture2, TextureOptions{Mipmaps: true, SRGB: *srgb}); err != nil {
+			return err
+		}
+		defer mixTexture.Delete()
+		textureWatches = append(textureWatches, watchTexture(mixTexture, *texture2))
+	}
 
-	for !window.ShouldClose() {
-		// clear buffer
-		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	var binder *autoBinder
+	var standard standardUniforms
+	state := RenderState{
+		DepthTest:  true,
+		DepthFunc:  proj.depthFunc(),
+		DepthWrite: *depthWrite,
+		Blend:      *blend,
+		ClearColor: mgl32.Vec3(clearColor).Vec4(1.0),
+	}
+	if *cull {
+		state.CullFace = gl.BACK
+	}
+	light := Light{
+		Direction: mgl32.Vec3{-0.5, 0.0, -1.0},
+		Color:     mgl32.Vec3{0.0, 0.5, 0.5},
+		Ambient:   0.1,
+		Shininess: 32.0,
+	}
+	material := PBRMaterial{
+		Albedo:    mgl32.Vec3{1.0, 1.0, 1.0},
+		Metallic:  float32(*metallic),
+		Roughness: float32(*roughness),
+		AO:        1.0,
+	}
 
-		matRot := mgl32.HomogRotate3DZ(float32(glfw.GetTime() - startTime))
-		gl.UniformMatrix4fv(uniModel, 1, false, &matRot[0])
+	// look up uniforms and set those that stay fixed, whenever the
+	// program is (re)linked
+	setup := func() {
+		binder = newAutoBinder(program)
+		program.Use()
 
-		gl.DrawArrays(gl.TRIANGLES, 0, int32(len(vertices)))
+		proj.upload(program)
+		program.SetInt("tex", 0)
+		program.SetInt("textured", boolToInt(sceneTexture != nil))
 
-		window.SwapBuffers()
-		glfw.PollEvents()
+		// samplers all read unit 0 until told otherwise
+		program.SetInt("tex2", 1)
+		program.SetInt("mixing", boolToInt(mixTexture != nil))
+
+		// uniforms missing from the phong shader are ignored
+		material.upload(program)
+		program.SetInt("framebufferSRGB", boolToInt(*srgb))
 	}
-}
+	setup()
 
-func newProgram(vertexShaderFile, fragmentShaderFile string) (uint32, error) {
-	// create shaders
-	vertexShader, err := compileShader(vertexShaderFile, gl.VERTEX_SHADER)
+	// shaders read from an asset directory are rebuilt when they are
+	// saved, keeping the last working program if they fail to build
+	var shaderWatch *fileWatcher
+	if *assetDir != "" {
+		shaderWatch = newFileWatcher(time.Second,
+			filepath.Join(*assetDir, *vert), filepath.Join(*assetDir, fragmentShader))
+	}
+
+	// solid color program for drawing edges over the shaded model
+	wire, err := NewProgram(assets, *vert, "solid_fragment.glsl")
 	if err != nil {
-		return 0, err
+		return err
 	}
+	defer wire.Delete()
+	wireBinder := newAutoBinder(wire)
+	wire.Use()
+	proj.upload(wire)
+	wire.SetVec3("color", mgl32.Vec3(wireColor))
 
-	fragmentShader, err := compileShader(fragmentShaderFile, gl.FRAGMENT_SHADER)
+	// solid color program for an outline around the model, drawing it a
+	// little bigger only where the model left no mark in the stencil
+	outline, err := NewProgram(assets, "outline_vertex.glsl", "solid_fragment.glsl")
 	if err != nil {
-		return 0, err
+		return err
 	}
+	defer outline.Delete()
+	outlineBinder := newAutoBinder(outline)
+	outline.Use()
+	outline.SetVec3("color", mgl32.Vec3{1.0, 0.5, 0.0})
+	outline.SetFloat("scale", 1.05)
 
-	// link shaders into program
-	program := gl.CreateProgram()
-	gl.AttachShader(program, vertexShader)
-	gl.AttachShader(program, fragmentShader)
-	gl.LinkProgram(program)
+	// optional floor grid, drawn with the same depth as the model so that
+	// the model hides it properly
+	var grid *Mesh
+	var gridProgram *Program
+	var gridBinder *autoBinder
+	if *gridSize > 0 {
+		if gridProgram, err = NewProgram(assets, "vertex.glsl", "solid_fragment.glsl"); err != nil {
+			return err
+		}
+		defer gridProgram.Delete()
+		gridBinder = newAutoBinder(gridProgram)
+		gridProgram.Use()
+		proj.upload(gridProgram)
+		gridProgram.SetVec3("color", mgl32.Vec3(gridColor))
 
-	// error handling
-	var status int32
-	gl.GetProgramiv(program, gl.LINK_STATUS, &status)
-	if status == gl.FALSE {
-		var logLength int32
-		gl.GetProgramiv(program, gl.INFO_LOG_LENGTH, &logLength)
+		grid = Grid(gridProgram, *gridSize, float32(*gridSpacing))
+		defer func() {
+			primary.window.MakeContextCurrent()
+			grid.Delete()
+		}()
+	}
 
-		log := strings.Repeat("\x00", int(logLength+1))
-		gl.GetProgramInfoLog(program, logLength, nil, gl.Str(log))
+	// optional floor of hills under the model, splatted with the layers of
+	// a texture array by height
+	var terrain *Mesh
+	var terrainProgram *Program
+	var terrainBinder *autoBinder
+	var splatLayers *Texture
+	if *splat != "" {
+		files := strings.Split(*splat, ",")
+		if splatLayers, err = LoadTextureArray(files, TextureOptions{Mipmaps: true, SRGB: *srgb}); err != nil {
+			return err
+		}
+		defer splatLayers.Delete()
+
+		if terrainProgram, err = NewProgram(assets, "vertex.glsl", "splat_fragment.glsl"); err != nil {
+			return err
+		}
+		defer terrainProgram.Delete()
+		terrainBinder = newAutoBinder(terrainProgram)
+		terrainProgram.Use()
+		proj.upload(terrainProgram)
+		terrainProgram.SetInt("layers", 0)
+		terrainProgram.SetInt("layerCount", int32(len(files)))
+
+		buffers, indices := terrainSurface(64)
+		terrain = NewSeparateMesh(terrainProgram, buffers, indices)
+		defer func() {
+			primary.window.MakeContextCurrent()
+			terrain.Delete()
+		}()
+	}
+
+	// m steps how much of -texture2 shows over -texture
+	var mixFactor float32 = 0.5
+	InputFor(primary.window).RegisterKeyBinding("M", "step the mix of -texture2 over -texture", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if key != glfw.KeyM || action != glfw.Press {
+			return false
+		}
+
+		mixFactor += 0.25
+		if mixFactor > 1.0 {
+			mixFactor = 0.0
+		}
+		return true
+	})
+
+	// l toggles the outline
+	outlined := false
+	InputFor(primary.window).RegisterKeyBinding("L", "toggle an outline around the model", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if key != glfw.KeyL || action != glfw.Press {
+			return false
+		}
+
+		outlined = !outlined
+		return true
+	})
+
+	// e toggles edges over the shaded model
+	edges := false
+	InputFor(primary.window).RegisterKeyBinding("E", "toggle edges over the shaded model", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if key != glfw.KeyE || action != glfw.Press {
+			return false
+		}
+
+		edges = !edges
+		return true
+	})
+
+	// o switches between perspective and parallel projection
+	InputFor(primary.window).RegisterKeyBinding("O", "toggle orthographic projection", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if key != glfw.KeyO || action != glfw.Press {
+			return false
+		}
+
+		proj.Ortho = !proj.Ortho
+		primary.window.MakeContextCurrent()
+		program.Use()
+		proj.upload(program)
+		wire.Use()
+		proj.upload(wire)
+		if grid != nil {
+			gridProgram.Use()
+			proj.upload(gridProgram)
+		}
+		if terrain != nil {
+			terrainProgram.Use()
+			proj.upload(terrainProgram)
+		}
+
+		return true
+	})
+
+	// f draws the model as shaded lines only, in place of its faces
+	wireframe := false
+	InputFor(primary.window).RegisterKeyBinding("F", "toggle wireframe", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if key != glfw.KeyF || action != glfw.Press {
+			return false
+		}
 
-		return 0, fmt.Errorf("failed to link program: %v", log)
+		wireframe = !wireframe
+		return true
+	})
+
+	// ctrl+v replaces the fragment shader with the clipboard contents
+	InputFor(primary.window).RegisterKeyBinding("Ctrl+V", "load a fragment shader from the clipboard", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if key != glfw.KeyV || action != glfw.Press || mods&glfw.ModControl == 0 {
+			return false
+		}
+
+		pasted, err := NewProgramFromSource(assets, *vert, primary.window.GetClipboardString())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return true
+		}
+
+		program.Delete()
+		program = pasted
+		setup()
+		fmt.Fprintln(os.Stderr, "loaded fragment shader from clipboard")
+
+		return true
+	})
+
+	// optional 2D overlay in the main window, of one image or of every
+	// region of an atlas, drawn from its one texture in a single batch
+	var sprites *SpriteBatch
+	var spriteTexture *Texture
+	var spriteAtlas *Atlas
+	if *sprite != "" {
+		primary.window.MakeContextCurrent()
+		if sprites, err = NewSpriteBatch(64); err != nil {
+			return err
+		}
+		defer func() {
+			// the batch's vao belongs to the main context
+			primary.window.MakeContextCurrent()
+			sprites.Delete()
+		}()
+
+		opts := TextureOptions{
+			WrapS:  gl.CLAMP_TO_EDGE,
+			WrapT:  gl.CLAMP_TO_EDGE,
+			NoFlip: true,
+			SRGB:   *srgb,
+		}
+		if strings.HasSuffix(*sprite, ".json") {
+			if spriteAtlas, err = LoadAtlas(*sprite, opts); err != nil {
+				return err
+			}
+			defer spriteAtlas.Delete()
+			textureWatches = append(textureWatches, watchTexture(spriteAtlas.Texture, spriteAtlas.Image))
+		} else {
+			if spriteTexture, err = NewTexture(diskFS{}, *sprite, opts); err != nil {
+				return err
+			}
+			defer spriteTexture.Delete()
+			textureWatches = append(textureWatches, watchTexture(spriteTexture, *sprite))
+		}
 	}
 
-	// clean up
-	gl.DeleteShader(vertexShader)
-	gl.DeleteShader(fragmentShader)
+	// optional text overlay in the main window
+	var hud *TextRenderer
+	if *hudFont != "" {
+		primary.window.MakeContextCurrent()
+		if hud, err = NewTextRenderer(*hudFont); err != nil {
+			return err
+		}
+		defer func() {
+			// the batch's vao belongs to the main context
+			primary.window.MakeContextCurrent()
+			hud.Delete()
+		}()
+	}
+
+	// optional cubemap background, drawn in every window
+	var sky *Skybox
+	if *skyFaces != "" {
+		files := strings.Split(*skyFaces, ",")
+		if len(files) != 6 {
+			return fmt.Errorf("skybox needs 6 faces, got %v", len(files))
+		}
+		var faces [6]string
+		copy(faces[:], files)
+
+		primary.window.MakeContextCurrent()
+		if sky, err = NewSkybox(faces, *srgb, &proj); err != nil {
+			return err
+		}
+		defer func() {
+			primary.window.MakeContextCurrent()
+			sky.Delete()
+		}()
+	}
+
+	// offscreen drawing renders the main window's scene into a texture,
+	// then covers the window with it
+	var offscreen *Framebuffer
+	var screen *ScreenQuad
+	if *drawOffscreen {
+		primary.window.MakeContextCurrent()
+		if offscreen, err = NewFramebuffer(primary.width, primary.height); err != nil {
+			return err
+		}
+		if screen, err = NewScreenQuad(assets, "post_fragment.glsl"); err != nil {
+			offscreen.Delete()
+			return err
+		}
+		screen.Program.Use()
+		screen.Program.SetInt("framebufferSRGB", boolToInt(*srgb))
+		defer func() {
+			// framebuffers belong to the context they were made in
+			primary.window.MakeContextCurrent()
+			screen.Delete()
+			offscreen.Delete()
+		}()
+	}
+
+	// x cycles through the effects applied on the way off the texture
+	effect := 0
+	InputFor(primary.window).RegisterKeyBinding("X", "cycle post-processing effects, with -offscreen", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if key != glfw.KeyX || action != glfw.Press {
+			return false
+		}
+
+		if screen == nil {
+			fmt.Fprintln(os.Stderr, "post-processing needs -offscreen")
+			return true
+		}
+		effect = (effect + 1) % len(postEffects)
+		fmt.Fprintf(os.Stderr, "effect: %v\n", postEffects[effect])
+
+		return true
+	})
+
+	// n switches between the model's own normals and one per face
+	InputFor(primary.window).RegisterKeyBinding("N", "toggle flat face normals", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if key != glfw.KeyN || action != glfw.Press {
+			return false
+		}
+
+		flat = !flat
+		primary.window.MakeContextCurrent()
+		show(shown)
+
+		return true
+	})
+
+	// b cycles the background through the preset colors
+	preset := 0
+	InputFor(primary.window).RegisterKeyBinding("B", "cycle the background color", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if key != glfw.KeyB || action != glfw.Press {
+			return false
+		}
+
+		preset = (preset + 1) % len(clearPresets)
+		state.ClearColor = clearPresets[preset].Vec4(1.0)
+
+		return true
+	})
+
+	var meter *audioMeter
+	if *audio != "" {
+		if meter, err = newAudioMeter(*audio); err != nil {
+			return err
+		}
+	}
 
-	return program, nil
+	model := math3d.NewTransform()
+	sceneBounds := boundsOf(shown.vertices, meshVertexSize)
+
+	// copies of the model are placed at these offsets, in diameters of
+	// the shown model so that they never overlap
+	instances := gridOffsets(*copies)
+
+	// the scene holds a node in place for each copy, all sharing the one
+	// turning shape
+	scene := NewNode()
+	scene.Material = &Material{Color: mgl32.Vec3(tint)}
+	shape := NewNode()
+	for range instances {
+		place := NewNode()
+		place.Add(shape)
+		scene.Add(place)
+	}
+
+	// drawCopies draws every copy with the bound program, each turning
+	// about its own center
+	drawCopies := func(p *Program) {
+		diameter := 2.0 * sceneBounds.Radius()
+		for i, offset := range instances {
+			scene.Children[i].Transform.Position = offset.Mul(diameter)
+		}
+		shape.Transform = model
+		shape.Mesh = mesh
+
+		scene.Draw(p, mgl32.Ident4())
+	}
+
+	// the main window's eye orbits the model with the mouse, for as long
+	// as the flying camera is off
+	orbit := NewOrbitCamera(primary.eye, primary.target)
+	orbit.MinRadius, orbit.MaxRadius = proj.Near, proj.Far
+
+	// scrolling with shift held, or while flying, zooms by narrowing the
+	// field of view instead of moving the eye, out no further than the
+	// zoom range or a wider starting -fov
+	widestZoom := float32(math.Max(maxZoomFOV, *fov))
+	InputFor(primary.window).RegisterScrollHandler(func(xoff, yoff float64) bool {
+		shift := primary.window.GetKey(glfw.KeyLeftShift) == glfw.Press ||
+			primary.window.GetKey(glfw.KeyRightShift) == glfw.Press
+		if orbit.Enabled && !shift {
+			return false
+		}
+
+		proj.FOV -= float32(yoff) * 2.0
+		if proj.FOV < minZoomFOV {
+			proj.FOV = minZoomFOV
+		} else if proj.FOV > widestZoom {
+			proj.FOV = widestZoom
+		}
+
+		return true
+	})
+	orbit.Attach(primary.window)
+
+	// frame fits a view around every copy of the model
+	frame := func(v *view) {
+		center := model.Matrix().Mul4x1(sceneBounds.Center().Vec4(1.0)).Vec3()
+		radius := sceneBounds.Radius() * (1.0 + 2.0*spread(instances))
+		v.frame(center, radius, proj.FOV)
+		if dist := v.eye.Sub(center).Len(); dist-radius < proj.Near || dist+radius > proj.Far {
+			fmt.Fprintf(os.Stderr, "model is clipped, try -near %.2f -far %.2f\n",
+				math.Max(float64(dist-radius), 0.01), dist+radius)
+		}
+		if v == primary {
+			orbit.LookAt(v.eye, v.target)
+		}
+	}
+
+	// z reframes the pressing window's view around the whole model
+	for _, v := range views {
+		v := v
+		InputFor(v.window).RegisterKeyBinding("Z", "frame the model in this view", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+			if key != glfw.KeyZ || action != glfw.Press {
+				return false
+			}
+
+			frame(v)
+			return true
+		})
+	}
+
+	// page up and down step through the models of a directory, showing
+	// each in place of the last and framing every view on it
+	InputFor(primary.window).RegisterKeyBinding("PageUp/PageDown", "step through the models of a directory", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if (key != glfw.KeyPageDown && key != glfw.KeyPageUp) || action != glfw.Press || *wave {
+			return false
+		}
+
+		delta := 1
+		if key == glfw.KeyPageUp {
+			delta = -1
+		}
+		library.Step(delta)
+
+		// stay on the current model if the next fails to parse
+		next, err := library.Model()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			library.Step(-delta)
+			return true
+		}
+		shown = next
+		sceneBounds = boundsOf(shown.vertices, meshVertexSize)
+
+		primary.window.MakeContextCurrent()
+		show(shown)
+
+		for _, v := range views {
+			frame(v)
+		}
+		fmt.Fprintf(os.Stderr, "showing %v\n", library.Name())
+
+		return true
+	})
+
+	spin := math3d.Spinner{
+		AngularVelocity: mgl32.Vec3{0.0, 0.0, 1.0},
+		Orientation:     mgl32.QuatIdent(),
+	}
+	startTime := glfw.GetTime()
+	lastTime := startTime
+	stats := frameStats{since: startTime}
+	hudFPS := 0.0
+
+	// space pauses the animation and r rewinds it to the start
+	clock := sceneClock{TimeScale: float32(*timeScale)}
+	InputFor(primary.window).RegisterKeyBinding("Space", "pause or resume the animation", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if key != glfw.KeySpace || action != glfw.Press {
+			return false
+		}
+
+		clock.Paused = !clock.Paused
+		return true
+	})
+	InputFor(primary.window).RegisterKeyBinding("R", "restart the animation", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if key != glfw.KeyR || action != glfw.Press {
+			return false
+		}
+
+		clock.Reset()
+		spin.Orientation = mgl32.QuatIdent()
+		return true
+	})
+
+	// g dumps the main window's render state to stdout
+	InputFor(primary.window).RegisterKeyBinding("G", "print the GL render state", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if key != glfw.KeyG || action != glfw.Press {
+			return false
+		}
+
+		primary.window.MakeContextCurrent()
+		printGLState(os.Stdout)
+		return true
+	})
+
+	// c flies the main window's eye around with WASD and the mouse in
+	// place of orbiting, leaving it wherever it ended up when toggled off
+	var camera *Camera
+	InputFor(primary.window).RegisterKeyBinding("C", "switch between flying the camera with WASD and the mouse and orbiting by dragging", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if key != glfw.KeyC || action != glfw.Press {
+			return false
+		}
+
+		if camera == nil {
+			camera = NewCamera(primary.eye, primary.target)
+			orbit.Enabled = false
+			CaptureCursor(primary.window)
+		} else {
+			// orbit the same point from wherever the flight ended
+			camera = nil
+			orbit.LookAt(primary.eye, orbit.Target)
+			orbit.Enabled = true
+			ReleaseCursor(primary.window)
+		}
+		return true
+	})
+
+	// -path drops a point whenever the flying camera has moved far enough
+	// and rebuilds a tube through them all, lit like the model
+	var pathPoints []mgl32.Vec3
+	var pathTube *Mesh
+	pathMaterial := Material{Color: mgl32.Vec3{1.0, 0.8, 0.2}}
+	defer func() {
+		if pathTube != nil {
+			primary.window.MakeContextCurrent()
+			pathTube.Delete()
+		}
+	}()
+
+	// -record samples the main window's frames into a GIF from the first
+	// frame on
+	var recording *gifRecorder
+	if *recordPath != "" {
+		recording = newGIFRecorder(*recordPath, *recordSeconds)
+	}
+
+	// p saves the main window's next frame to a timestamped PNG
+	screenshot := false
+	InputFor(primary.window).RegisterKeyBinding("P", "save a screenshot", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if key != glfw.KeyP || action != glfw.Press {
+			return false
+		}
+
+		screenshot = true
+		return true
+	})
+
+	// f12 records everything the next frame does to a file
+	InputFor(primary.window).RegisterKeyBinding("F12", "capture the next frame to a file", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if key != glfw.KeyF12 || action != glfw.Press {
+			return false
+		}
+
+		capture.request()
+		return true
+	})
+
+	// f11 switches the main window between fullscreen and windowed
+	InputFor(primary.window).RegisterKeyBinding("F11", "toggle fullscreen", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+		if key != glfw.KeyF11 || action != glfw.Press {
+			return false
+		}
+
+		window.ToggleFullscreen()
+		return true
+	})
+
+	// escape closes the pressing window, and the main window takes the
+	// others with it
+	for _, v := range views {
+		EnableEscapeToClose(v.window)
+	}
+
+	// h or f1 lists the key bindings of the pressing window, registered
+	// last so every other binding is already described. With -font the
+	// main window toggles them over the scene instead of printing them.
+	helpText := ""
+	for _, v := range views {
+		in := InputFor(v.window)
+		overlay := hud != nil && v == primary
+		in.RegisterKeyBinding("H/F1", "show or list key bindings", func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) bool {
+			if (key != glfw.KeyH && key != glfw.KeyF1) || action != glfw.Press {
+				return false
+			}
+
+			if !overlay {
+				in.PrintBindings(os.Stdout)
+			} else if helpText != "" {
+				helpText = ""
+			} else {
+				var b strings.Builder
+				in.PrintBindings(&b)
+				helpText = b.String()
+			}
+			return true
+		})
+	}
+
+	// the main window owns the scene, closing it ends the program
+	for !window.ShouldClose() {
+		frameStart := time.Now()
+		capture.begin()
+
+		// pick up edits to the scene textures and the overlay image
+		if len(textureWatches) > 0 {
+			primary.window.MakeContextCurrent()
+			for _, w := range textureWatches {
+				w.reloadTexture()
+			}
+		}
+
+		if shaderWatch != nil && shaderWatch.changed() {
+			primary.window.MakeContextCurrent()
+			if reloaded, err := NewProgram(assets, *vert, fragmentShader); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			} else {
+				program.Delete()
+				program = reloaded
+				setup()
+				fmt.Fprintf(os.Stderr, "reloaded %v and %v\n", *vert, fragmentShader)
+			}
+		}
+
+		now := glfw.GetTime()
+		dt := float32(now - lastTime)
+		if (*showFPS || hud != nil) && stats.add(now, now-lastTime) {
+			hudFPS = stats.fps()
+			if *showFPS {
+				fmt.Fprintln(os.Stderr, stats.String())
+				primary.window.SetTitle(fmt.Sprintf("%v (%.0f fps)", primary.title, hudFPS))
+			}
+			stats.reset(now)
+		}
+		lastTime = now
+		spin.Update(clock.Advance(dt))
+		standard.Time = clock.Time
+
+		if camera != nil {
+			camera.Update(primary.window, dt)
+			primary.eye = camera.Position
+			primary.target = camera.Position.Add(camera.Front)
+
+			spacing := 0.25 * sceneBounds.Radius()
+			if n := len(pathPoints); *showPath && (n == 0 || camera.Position.Sub(pathPoints[n-1]).Len() >= spacing) {
+				pathPoints = append(pathPoints, camera.Position)
+				primary.window.MakeContextCurrent()
+				if pathTube != nil {
+					pathTube.Delete()
+				}
+				pathTube = GenTubeAlongSpline(pathPoints, 0.02*sceneBounds.Radius(), 8, program)
+			}
+		} else {
+			primary.eye, primary.target = orbit.Eye(), orbit.Target
+		}
+
+		model.Rotation = spin.Orientation
+		if *wave {
+			surface := waveSurface(waveSquares, clock.Time)
+			if flat {
+				surface = flatShaded(surface)
+			}
+			primary.window.MakeContextCurrent()
+			mesh.Update(surface.vertices)
+		}
+		if meter != nil {
+			standard.AudioLevel = meter.Level()
+		}
+		standard.Model = model.Matrix()
+
+		for i := 0; i < len(views); i++ {
+			v := views[i]
+
+			// secondary windows can be closed on their own
+			if v != primary && v.window.ShouldClose() {
+				v.destroy()
+				views = append(views[:i], views[i+1:]...)
+				i--
+				continue
+			}
+
+			v.window.MakeContextCurrent()
+			program.Use()
+			gl.Viewport(0, 0, int32(v.width), int32(v.height))
+			capture.logf("window %q: viewport %vx%v, program %v", v.title, v.width, v.height, program.ID)
+
+			// a minimised window has no pixels to draw offscreen
+			toTexture := v == primary && offscreen != nil && v.width > 0 && v.height > 0
+			if toTexture {
+				if offscreen.Width != v.width || offscreen.Height != v.height {
+					if err := offscreen.Resize(v.width, v.height); err != nil {
+						return err
+					}
+				}
+				offscreen.Bind()
+			}
+
+			// earlier passes leave their own state behind
+			state.Apply()
+			state.Clear()
+
+			standard.Proj = proj.Matrix(v.aspect(), v.eye.Sub(v.target).Len())
+			standard.View = v.viewMatrix()
+			standard.CameraPos = v.eye
+			standard.Resolution = v.resolution()
+
+			// the sky normally goes last so the model hides all it can,
+			// but a model that writes no depth cannot hide it
+			if sky != nil && !state.DepthWrite {
+				sky.Draw(standard.View, standard.Proj)
+				state.Apply()
+				program.Use()
+			}
+
+			// the floor sits under the model wherever it has turned to
+			if grid != nil {
+				floor := sceneBounds.Center()
+				floor[2] -= sceneBounds.Radius()
+
+				gridProgram.Use()
+				gridBinder.apply(&standard)
+				gridProgram.SetMat4("model", mgl32.Translate3D(floor[0], floor[1], floor[2]))
+				capture.logf("grid: program %v", gridProgram.ID)
+				grid.Draw()
+				program.Use()
+			}
+
+			// the hills' tops meet the bottom of the model, spreading out
+			// under all of its copies
+			if terrain != nil {
+				size := 2.0 * sceneBounds.Radius() * float32(*copies)
+				floor := sceneBounds.Center()
+				floor[2] -= sceneBounds.Radius() + terrainHeight*size
+
+				terrainProgram.Use()
+				terrainBinder.apply(&standard)
+				light.upload(terrainProgram)
+				terrainProgram.SetMat4("model", mgl32.Translate3D(floor[0], floor[1], floor[2]).Mul4(mgl32.Scale3D(size, size, size)))
+				terrainProgram.SetVec2("heightRange", mgl32.Vec2{floor[2] - terrainHeight*size, floor[2] + terrainHeight*size})
+				splatLayers.Bind(0)
+				capture.logf("terrain: program %v", terrainProgram.ID)
+				terrain.Draw()
+				program.Use()
+			}
+
+			binder.apply(&standard)
+			light.upload(program)
+
+			// bindings belong to each context, and sprites use unit 0 too
+			if sceneTexture != nil {
+				sceneTexture.Bind(0)
+			}
+			if mixTexture != nil {
+				mixTexture.Bind(1)
+				program.SetFloat("mixFactor", mixFactor)
+			}
+
+			// push the shaded faces back so the edges drawn over them win
+			// the depth test, which wireframe has no faces to need
+			if wireframe {
+				gl.PolygonMode(gl.FRONT_AND_BACK, gl.LINE)
+			} else if edges {
+				gl.Enable(gl.POLYGON_OFFSET_FILL)
+				gl.PolygonOffset(proj.offsetSign(), proj.offsetSign())
+			}
+
+			// the model marks where it was drawn for the outline to avoid
+			if outlined {
+				marked := state
+				marked.Stencil = StencilState{Func: gl.ALWAYS, Ref: 1, Pass: gl.REPLACE}
+				marked.Apply()
+			}
+
+			drawCopies(program)
+			if pathTube != nil {
+				program.SetMat4("model", mgl32.Ident4())
+				pathMaterial.upload(program)
+				capture.logf("camera path: %v points", len(pathPoints))
+				pathTube.Draw()
+			}
+
+			if wireframe {
+				gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
+			} else if edges {
+				gl.Disable(gl.POLYGON_OFFSET_FILL)
+
+				wire.Use()
+				wireBinder.apply(&standard)
+				gl.PolygonMode(gl.FRONT_AND_BACK, gl.LINE)
+				capture.logf("edges: program %v", wire.ID)
+				drawCopies(wire)
+				gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
+			}
+
+			if sky != nil && state.DepthWrite {
+				sky.Draw(standard.View, standard.Proj)
+			}
+
+			// the outline goes over everything but the model itself, so
+			// is not depth tested
+			if outlined {
+				RenderState{CullFace: state.CullFace, Stencil: StencilState{Func: gl.NOTEQUAL, Ref: 1}}.Apply()
+				outline.Use()
+				outlineBinder.apply(&standard)
+				outline.SetVec3("center", sceneBounds.Center())
+				capture.logf("outline: program %v", outline.ID)
+				drawCopies(outline)
+			}
+
+			if toTexture {
+				offscreen.Unbind()
+				gl.Viewport(0, 0, int32(v.width), int32(v.height))
+				screen.Program.Use()
+				screen.Program.SetInt("effect", int32(effect))
+				screen.Draw(offscreen.ColorTexture())
+			}
+
+			if v == primary && sprites != nil {
+				white := mgl32.Vec4{1.0, 1.0, 1.0, 1.0}
+				sprites.Begin(v.width, v.height)
+				if spriteAtlas != nil {
+					// regions side by side at their size in the atlas
+					pos := mgl32.Vec2{8.0, 8.0}
+					for _, name := range spriteAtlas.Names() {
+						uv, _ := spriteAtlas.Region(name)
+						size := mgl32.Vec2{(uv[2] - uv[0]) * float32(spriteAtlas.Width), (uv[3] - uv[1]) * float32(spriteAtlas.Height)}
+						sprites.Draw(spriteAtlas.Texture, pos, size, uv, white)
+						pos[0] += size[0] + 8.0
+					}
+				} else {
+					sprites.Draw(spriteTexture, mgl32.Vec2{8.0, 8.0}, mgl32.Vec2{128.0, 128.0},
+						mgl32.Vec4{0.0, 0.0, 1.0, 1.0}, white)
+				}
+				sprites.End()
+			}
+
+			if v == primary && hud != nil {
+				hud.Begin(v.width, v.height)
+				hud.DrawText(fmt.Sprintf("%.0f fps\ncamera %.2f, %.2f, %.2f",
+					hudFPS, v.eye[0], v.eye[1], v.eye[2]), 8.0, 8.0, 2.0)
+				if helpText != "" {
+					// smaller, a line clear of the two above
+					hud.DrawText(helpText, 8.0, 8.0+2.0*hud.LineHeight(2.0)+hud.LineHeight(1.0), 1.0)
+				}
+				hud.End()
+			}
+
+			if v == primary && screenshot {
+				screenshot = false
+				file := time.Now().Format("screenshot-20060102-150405.png")
+				if err := captureScreenshot(v.window, file); err != nil {
+					fmt.Fprintf(os.Stderr, "failed to save screenshot: %v\n", err)
+				} else {
+					fmt.Fprintf(os.Stderr, "saved %v\n", file)
+				}
+			}
+
+			if v == primary && recording != nil {
+				done, err := recording.frame(v.window, now)
+				if done {
+					recording = nil
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "failed to save recording: %v\n", err)
+					} else {
+						fmt.Fprintf(os.Stderr, "saved %v\n", *recordPath)
+					}
+					if *recordExit {
+						window.Handle.SetShouldClose(true)
+					}
+				}
+			}
+
+			v.window.SwapBuffers()
+		}
+
+		capture.end()
+		window.PollEvents()
+
+		// sleep off what is left of the frame's budget; animation uses
+		// elapsed time so it keeps its speed at any rate
+		if *fpsCap > 0 {
+			budget := time.Second / time.Duration(*fpsCap)
+			if elapsed := time.Since(frameStart); elapsed < budget {
+				time.Sleep(budget - elapsed)
+			}
+		}
+	}
+
+	return nil
 }
 
-func compileShader(sourceFile string, shaderType uint32) (uint32, error) {
+func compileShader(fsys fs.FS, sourceFile string, shaderType uint32) (uint32, error) {
 	// read shader source from file
-	sourceBytes, err := ioutil.ReadFile(sourceFile)
+	sourceBytes, err := fs.ReadFile(fsys, sourceFile)
 	if err != nil {
 		return 0, err
 	}
+
+	return compileShaderSource(string(sourceBytes), sourceFile, shaderType)
+}
+
+// compileShaderSource compiles shader source, naming it in any error
+func compileShaderSource(source, name string, shaderType uint32) (uint32, error) {
 	// allow use as a C string
-	csource := gl.Str(string(sourceBytes) + "\x00")
+	csource := gl.Str(source + "\x00")
 
 	// load into OpenGL
 	shader := gl.CreateShader(shaderType)
@@ -176,35 +1237,46 @@ func compileShader(sourceFile string, shaderType uint32) (uint32, error) {
 
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
+		gl.DeleteShader(shader)
 
-		return 0, fmt.Errorf("failed to compile %v: %v", sourceFile, log)
+		return 0, fmt.Errorf("failed to compile %v: %v", name, log)
 	}
 
 	return shader, nil
 }
 
-func newTexture(file string, texNum uint32) (uint32, error) {
-	imgFile, err := os.Open(file)
+// loadImage decodes a PNG, JPEG or GIF image file
+func loadImage(fsys fs.FS, file string) (image.Image, error) {
+	imgFile, err := fsys.Open(file)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	img, _, err := image.Decode(imgFile)
+	defer imgFile.Close()
 
-	rgba := image.NewRGBA(img.Bounds())
-	if rgba.Stride != rgba.Rect.Size().X*4 {
-		return 0, fmt.Errorf("unsupported stride")
+	img, format, err := image.Decode(imgFile)
+	if err == image.ErrFormat {
+		return nil, fmt.Errorf("failed to decode %v: not a PNG, JPEG or GIF image", file)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to decode %v as %v: %v", file, format, err)
 	}
-	draw.Draw(rgba, rgba.Bounds(), img, image.ZP, draw.Src)
 
-	var texture uint32
-	gl.GenTextures(1, &texture)
-	gl.ActiveTexture(texNum)
-	gl.BindTexture(gl.TEXTURE_2D, texture)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
-	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA,
-		int32(rgba.Rect.Size().X), int32(rgba.Rect.Size().Y),
-		0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
+	return img, nil
+}
+
+// loadIcons decodes a comma-separated list of icon images
+func loadIcons(fsys fs.FS, files string) ([]image.Image, error) {
+	var icons []image.Image
+	for _, file := range strings.Split(files, ",") {
+		if file == "" {
+			continue
+		}
+
+		img, err := loadImage(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		icons = append(icons, img)
+	}
 
-	return texture, nil
+	return icons, nil
 }
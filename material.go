@@ -0,0 +1,36 @@
+package main
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Material tints whatever is drawn with it through the materialColor
+// uniform of fragment.glsl and pbr.glsl, so one mesh can be drawn in
+// several colors
+type Material struct {
+	Color mgl32.Vec3
+}
+
+// defaultMaterial leaves surfaces their own color
+var defaultMaterial = Material{Color: mgl32.Vec3{1.0, 1.0, 1.0}}
+
+// upload sets the material uniforms on the currently bound program
+func (m *Material) upload(program *Program) {
+	program.SetVec3("materialColor", m.Color)
+}
+
+// PBRMaterial holds the metallic-roughness inputs of pbr.glsl
+type PBRMaterial struct {
+	Albedo    mgl32.Vec3
+	Metallic  float32
+	Roughness float32
+	AO        float32
+}
+
+// upload sets the material uniforms on the currently bound program
+func (m *PBRMaterial) upload(program *Program) {
+	program.SetVec3("albedo", m.Albedo)
+	program.SetFloat("metallic", m.Metallic)
+	program.SetFloat("roughness", m.Roughness)
+	program.SetFloat("ao", m.AO)
+}
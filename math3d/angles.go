@@ -0,0 +1,33 @@
+package math3d
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+)
+
+// Direction is the unit vector at yaw degrees about the z axis from +x
+// and pitch degrees up towards +z
+func Direction(yaw, pitch float32) mgl32.Vec3 {
+	y := float64(mgl32.DegToRad(yaw))
+	p := float64(mgl32.DegToRad(pitch))
+
+	return mgl32.Vec3{
+		float32(math.Cos(p) * math.Cos(y)),
+		float32(math.Cos(p) * math.Sin(y)),
+		float32(math.Sin(p)),
+	}
+}
+
+// YawPitch is the inverse of Direction, for any non-zero vector
+func YawPitch(dir mgl32.Vec3) (yaw, pitch float32) {
+	dir = dir.Normalize()
+	yaw = mgl32.RadToDeg(float32(math.Atan2(float64(dir[1]), float64(dir[0]))))
+	pitch = mgl32.RadToDeg(float32(math.Asin(float64(mgl32.Clamp(dir[2], -1.0, 1.0)))))
+
+	return yaw, pitch
+}
+
+// LookAt views target from eye with z up
+func LookAt(eye, target mgl32.Vec3) mgl32.Mat4 {
+	return mgl32.LookAtV(eye, target, mgl32.Vec3{0.0, 0.0, 1.0})
+}
@@ -0,0 +1,58 @@
+package math3d
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"testing"
+)
+
+// angles come back through asin and atan2 a little less precisely
+const angleEpsilon = 1e-3
+
+func TestDirectionYawPitch(t *testing.T) {
+	for _, angles := range [][2]float32{
+		{0.0, 0.0},
+		{90.0, 0.0},
+		{-135.0, 45.0},
+		{30.0, -89.0},
+		{179.0, 60.0},
+	} {
+		dir := Direction(angles[0], angles[1])
+		if !approx(dir.Len(), 1.0) {
+			t.Errorf("direction %v has length %v", angles, dir.Len())
+		}
+
+		yaw, pitch := YawPitch(dir)
+		if math.Abs(float64(yaw-angles[0])) > angleEpsilon || math.Abs(float64(pitch-angles[1])) > angleEpsilon {
+			t.Errorf("yaw and pitch of %v are %v, %v", angles, yaw, pitch)
+		}
+	}
+
+	// any length of vector gives the same angles
+	yaw, pitch := YawPitch(mgl32.Vec3{0.0, 3.0, 3.0})
+	if math.Abs(float64(yaw-90.0)) > angleEpsilon || math.Abs(float64(pitch-45.0)) > angleEpsilon {
+		t.Errorf("yaw and pitch of 0, 3, 3 are %v, %v, want 90, 45", yaw, pitch)
+	}
+}
+
+func TestLookAtZUp(t *testing.T) {
+	eye := mgl32.Vec3{3.0, -4.0, 2.0}
+	target := mgl32.Vec3{0.0, 1.0, 0.5}
+
+	view := LookAt(eye, target)
+	if want := mgl32.LookAtV(eye, target, mgl32.Vec3{0.0, 0.0, 1.0}); !approxMat4(view, want) {
+		t.Errorf("view %v, want %v", view, want)
+	}
+
+	// the eye is at the origin looking down -z, with world up above it
+	if got := mgl32.TransformCoordinate(eye, view); !approxVec3(got, mgl32.Vec3{}) {
+		t.Errorf("eye moved to %v, want the origin", got)
+	}
+	got := mgl32.TransformCoordinate(target, view)
+	if want := (mgl32.Vec3{0.0, 0.0, -target.Sub(eye).Len()}); !approxVec3(got, want) {
+		t.Errorf("target moved to %v, want %v", got, want)
+	}
+	if up := mgl32.TransformNormal(mgl32.Vec3{0.0, 0.0, 1.0}, view); up[1] <= 0.0 {
+		t.Errorf("world up is %v in view space, want it above the eye", up)
+	}
+}
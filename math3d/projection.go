@@ -0,0 +1,40 @@
+package math3d
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+)
+
+// ReversedPerspective is a perspective projection for a [0, 1] clip depth
+// range, with depth running from 1 at the near plane to 0 at the far
+// plane. fov is the vertical field of view.
+func ReversedPerspective(fov, aspect, near, far float32) mgl32.Mat4 {
+	// clip z is near * (far + z) / (far - near), so depth z/w runs from
+	// 1 at the near plane to 0 at the far plane
+	f := float32(1.0 / math.Tan(float64(mgl32.DegToRad(fov))/2.0))
+	m := mgl32.Mat4{}
+	m[0] = f / aspect
+	m[5] = f
+	m[10] = near / (far - near)
+	m[11] = -1.0
+	m[14] = far * near / (far - near)
+
+	return m
+}
+
+// FocusOrtho is a parallel projection covering what a perspective one of
+// vertical field of view fov would at the focus distance, reversed into a
+// [0, 1] depth range like ReversedPerspective if asked
+func FocusOrtho(fov, aspect, focus, near, far float32, reversed bool) mgl32.Mat4 {
+	top := focus * float32(math.Tan(float64(mgl32.DegToRad(fov))/2.0))
+	right := top * aspect
+
+	m := mgl32.Ortho(-right, right, -top, top, near, far)
+	if reversed {
+		// depth runs linearly from 1 at the near plane to 0 at the far
+		m[10] = 1.0 / (far - near)
+		m[14] = far / (far - near)
+	}
+
+	return m
+}
@@ -0,0 +1,35 @@
+package math3d
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"testing"
+)
+
+// depth is the clip depth a projection gives a point at distance along
+// the view direction
+func depth(proj mgl32.Mat4, distance float32) float32 {
+	clip := proj.Mul4x1(mgl32.Vec4{0.0, 0.0, -distance, 1.0})
+	return clip[2] / clip[3]
+}
+
+func TestReversedPerspective(t *testing.T) {
+	const near, far = 0.1, 100.0
+	proj := ReversedPerspective(45.0, 16.0/9.0, near, far)
+
+	if d := depth(proj, near); !approx(d, 1.0) {
+		t.Errorf("near plane depth %v, want 1", d)
+	}
+	if d := depth(proj, far); !approx(d, 0.0) {
+		t.Errorf("far plane depth %v, want 0", d)
+	}
+
+	// depth falls the whole way between them
+	last := float32(1.0)
+	for distance := float32(0.5); distance < far; distance *= 2.0 {
+		d := depth(proj, distance)
+		if d >= last || d <= 0.0 {
+			t.Errorf("depth %v at %v does not fall between %v and 0", d, distance, last)
+		}
+		last = d
+	}
+}
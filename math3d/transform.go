@@ -0,0 +1,75 @@
+// Package math3d holds the transform, camera and projection math of the
+// viewer, kept free of OpenGL and GLFW so it can be used and checked
+// without a context. The scene is z up, and angles are in degrees unless
+// noted.
+package math3d
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Transform places an object in the world by position, orientation and
+// scale
+type Transform struct {
+	Position mgl32.Vec3
+	Rotation mgl32.Quat
+	Scale    mgl32.Vec3
+}
+
+// NewTransform returns the identity transform
+func NewTransform() Transform {
+	return Transform{
+		Rotation: mgl32.QuatIdent(),
+		Scale:    mgl32.Vec3{1.0, 1.0, 1.0},
+	}
+}
+
+// Matrix composes translation * rotation * scale, so that objects are
+// scaled, then rotated, then moved into place
+func (t *Transform) Matrix() mgl32.Mat4 {
+	return mgl32.Translate3D(t.Position[0], t.Position[1], t.Position[2]).
+		Mul4(t.Rotation.Mat4()).
+		Mul4(mgl32.Scale3D(t.Scale[0], t.Scale[1], t.Scale[2]))
+}
+
+// Interpolate blends from t towards to by amount in [0, 1], moving and
+// scaling in a straight line and turning along the shortest arc
+func (t *Transform) Interpolate(to Transform, amount float32) Transform {
+	return Transform{
+		Position: t.Position.Add(to.Position.Sub(t.Position).Mul(amount)),
+		Rotation: mgl32.QuatSlerp(t.Rotation, to.Rotation, amount),
+		Scale:    t.Scale.Add(to.Scale.Sub(t.Scale).Mul(amount)),
+	}
+}
+
+// SetEulerAngles sets the rotation from angles in radians about the x, y
+// and z axes, applied in that order
+func (t *Transform) SetEulerAngles(x, y, z float32) {
+	t.Rotation = mgl32.AnglesToQuat(z, y, x, mgl32.ZYX)
+}
+
+// LookAt turns the object so that its front (-z) faces target with its
+// top (+y) towards up
+func (t *Transform) LookAt(target, up mgl32.Vec3) {
+	// the transposed view matrix is the object's orientation
+	t.Rotation = mgl32.Mat4ToQuat(mgl32.LookAtV(t.Position, target, up).Transpose())
+}
+
+// Spinner integrates an angular velocity into an orientation, avoiding
+// the gimbal lock of accumulating euler angles
+type Spinner struct {
+	// radians per second about each world axis
+	AngularVelocity mgl32.Vec3
+	Orientation     mgl32.Quat
+}
+
+// Update advances the orientation by dt seconds
+func (s *Spinner) Update(dt float32) {
+	speed := s.AngularVelocity.Len()
+	if speed == 0.0 {
+		return
+	}
+
+	step := mgl32.QuatRotate(speed*dt, s.AngularVelocity.Mul(1.0/speed))
+	s.Orientation = step.Mul(s.Orientation).Normalize()
+}
@@ -0,0 +1,63 @@
+package math3d
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"testing"
+)
+
+// tolerance for comparing results of float32 math
+const epsilon = 1e-5
+
+// approx reports whether a and b differ by at most epsilon, unlike
+// mgl32's comparisons, which tighten near zero
+func approx(a, b float32) bool {
+	return math.Abs(float64(a-b)) <= epsilon
+}
+
+// approxVec3 compares vectors component by component
+func approxVec3(a, b mgl32.Vec3) bool {
+	return approx(a[0], b[0]) && approx(a[1], b[1]) && approx(a[2], b[2])
+}
+
+// approxMat4 compares matrices element by element
+func approxMat4(a, b mgl32.Mat4) bool {
+	for i := range a {
+		if !approx(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTransformMatrixRoundTrip(t *testing.T) {
+	tr := NewTransform()
+	tr.Position = mgl32.Vec3{1.0, -2.0, 3.0}
+	tr.SetEulerAngles(0.3, -1.1, 2.0)
+	tr.Scale = mgl32.Vec3{2.0, 0.5, 3.0}
+	m := tr.Matrix()
+
+	// translation is the last column, and each basis column is a rotated
+	// axis stretched by its scale
+	if position := m.Col(3).Vec3(); !approxVec3(position, tr.Position) {
+		t.Errorf("position %v, want %v", position, tr.Position)
+	}
+	var rotation mgl32.Mat3
+	for i := 0; i < 3; i++ {
+		axis := m.Col(i).Vec3()
+		if scale := axis.Len(); !approx(scale, tr.Scale[i]) {
+			t.Errorf("scale %v is %v, want %v", i, scale, tr.Scale[i])
+		}
+		rotation.SetCol(i, axis.Normalize())
+	}
+	if q := mgl32.Mat4ToQuat(rotation.Mat4()); !q.OrientationEqualThreshold(tr.Rotation, epsilon) {
+		t.Errorf("rotation %v, want %v", q, tr.Rotation)
+	}
+
+	// objects are scaled, then rotated, then moved
+	p := mgl32.Vec3{0.5, 1.0, -1.5}
+	want := tr.Position.Add(tr.Rotation.Rotate(mgl32.Vec3{p[0] * 2.0, p[1] * 0.5, p[2] * 3.0}))
+	if got := mgl32.TransformCoordinate(p, m); !approxVec3(got, want) {
+		t.Errorf("transformed %v to %v, want %v", p, got, want)
+	}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"fmt"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// floats per mesh vertex: position, normal and texture coordinate
+const meshVertexSize = 3 + 3 + 2
+
+// VertexAttrib describes one attribute of interleaved vertex data, with
+// its size and offset counted in floats
+type VertexAttrib struct {
+	Name   string
+	Size   int32
+	Offset int
+}
+
+// meshAttribs is the layout of models and built-in shapes
+var meshAttribs = []VertexAttrib{
+	{"position", 3, 0},
+	{"normal", 3, 3},
+	{"texCoord", 2, 6},
+}
+
+// AttribBuffer is one attribute's values for every vertex, tightly
+// packed in a buffer of its own, as glTF stores non-interleaved accessors
+type AttribBuffer struct {
+	Name string
+	Size int32
+	Data []float32
+}
+
+// meshAttrib is an attribute resolved to its location in a program, read
+// from one of the mesh's buffers with a stride and offset in floats
+type meshAttrib struct {
+	buffer   uint32
+	location uint32
+	size     int32
+	stride   int
+	offset   int
+}
+
+// Mesh is triangle data uploaded to the GPU, or lines when Primitive is
+// set to gl.LINES, drawn indexed when it has indices. Vertices are either
+// interleaved in one buffer or split into one buffer per attribute.
+// Buffers are shared between all windows, but vertex array objects are
+// not, so the mesh sets one up for each context it is drawn in. Those die
+// with their context, and Delete only frees the current context's.
+type Mesh struct {
+	Primitive uint32
+
+	// one interleaved buffer, or one per attribute
+	vbos    []uint32
+	ebo     uint32
+	count   int32
+	stride  int
+	attribs []meshAttrib
+	vaos    map[*glfw.Window]uint32
+
+	// buffer usage hint, and the vertex buffer's size in bytes
+	usage uint32
+	size  int
+}
+
+// NewMesh uploads interleaved vertices laid out as attribs, with indices
+// into them or nil to draw the vertices in order. Attribute locations are
+// looked up in program, skipping any it does not use, and the mesh can
+// be drawn with any program sharing those locations.
+func NewMesh(program *Program, vertices []float32, indices []uint32, attribs []VertexAttrib) *Mesh {
+	return newMesh(program, vertices, indices, attribs, gl.STATIC_DRAW)
+}
+
+// NewDynamicMesh is NewMesh for vertices that are replaced with Update
+// as often as every frame
+func NewDynamicMesh(program *Program, vertices []float32, indices []uint32, attribs []VertexAttrib) *Mesh {
+	return newMesh(program, vertices, indices, attribs, gl.DYNAMIC_DRAW)
+}
+
+func newMesh(program *Program, vertices []float32, indices []uint32, attribs []VertexAttrib, usage uint32) *Mesh {
+	m := &Mesh{Primitive: gl.TRIANGLES, vaos: map[*glfw.Window]uint32{}, usage: usage}
+	for _, attrib := range attribs {
+		if end := attrib.Offset + int(attrib.Size); end > m.stride {
+			m.stride = end
+		}
+	}
+
+	var vbo uint32
+	gl.GenBuffers(1, &vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	m.size = len(vertices) * 4
+	gl.BufferData(gl.ARRAY_BUFFER, m.size, gl.Ptr(vertices), usage)
+	m.vbos = []uint32{vbo}
+
+	for _, attrib := range attribs {
+		m.addAttrib(program, attrib.Name, vbo, attrib.Size, m.stride, attrib.Offset)
+	}
+	if m.stride > 0 {
+		m.count = int32(len(vertices) / m.stride)
+	}
+
+	m.upload(indices)
+	return m
+}
+
+// NewSeparateMesh uploads each attribute's values to its own buffer, with
+// indices into them or nil to draw the vertices in order. Every buffer
+// must hold the same number of vertices, and it panics if they do not.
+// Meshes made this way cannot be updated.
+func NewSeparateMesh(program *Program, buffers []AttribBuffer, indices []uint32) *Mesh {
+	count, strides, err := separateLayout(buffers)
+	if err != nil {
+		panic("NewSeparateMesh: " + err.Error())
+	}
+
+	m := &Mesh{Primitive: gl.TRIANGLES, vaos: map[*glfw.Window]uint32{}, usage: gl.STATIC_DRAW, count: count}
+	for i, buffer := range buffers {
+		var vbo uint32
+		gl.GenBuffers(1, &vbo)
+		gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+		gl.BufferData(gl.ARRAY_BUFFER, len(buffer.Data)*4, gl.Ptr(buffer.Data), gl.STATIC_DRAW)
+		m.vbos = append(m.vbos, vbo)
+
+		m.addAttrib(program, buffer.Name, vbo, buffer.Size, strides[i], 0)
+	}
+
+	m.upload(indices)
+	return m
+}
+
+// separateLayout checks the buffers of a separate mesh, returning how
+// many vertices they hold and each one's stride in floats, which is its
+// attribute's size as the values are tightly packed
+func separateLayout(buffers []AttribBuffer) (int32, []int, error) {
+	if len(buffers) == 0 {
+		return 0, nil, fmt.Errorf("no attribute buffers")
+	}
+
+	count := 0
+	strides := make([]int, len(buffers))
+	for i, buffer := range buffers {
+		if buffer.Size < 1 || buffer.Size > 4 {
+			return 0, nil, fmt.Errorf("attribute %v has size %v, not 1 to 4", buffer.Name, buffer.Size)
+		}
+		if len(buffer.Data)%int(buffer.Size) != 0 {
+			return 0, nil, fmt.Errorf("attribute %v has %v values, not a whole number of vertices of %v",
+				buffer.Name, len(buffer.Data), buffer.Size)
+		}
+
+		n := len(buffer.Data) / int(buffer.Size)
+		if i == 0 {
+			count = n
+		} else if n != count {
+			return 0, nil, fmt.Errorf("attribute %v has %v vertices, but %v has %v",
+				buffer.Name, n, buffers[0].Name, count)
+		}
+		strides[i] = int(buffer.Size)
+	}
+
+	return int32(count), strides, nil
+}
+
+// addAttrib reads the named attribute from buffer, skipping it if the
+// program does not use it
+func (m *Mesh) addAttrib(program *Program, name string, buffer uint32, size int32, stride, offset int) {
+	loc := gl.GetAttribLocation(program.ID, gl.Str(name+"\x00"))
+	if loc < 0 {
+		return
+	}
+	m.attribs = append(m.attribs, meshAttrib{buffer, uint32(loc), size, stride, offset})
+}
+
+// upload stores any indices, which then set the number drawn
+func (m *Mesh) upload(indices []uint32) {
+	if indices == nil {
+		return
+	}
+
+	// the element buffer binding belongs to whichever vao is bound, so
+	// fill it through the array buffer target and leave attaching it to
+	// each context's vao
+	m.count = int32(len(indices))
+	gl.GenBuffers(1, &m.ebo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, m.ebo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(indices)*4, gl.Ptr(indices), gl.STATIC_DRAW)
+}
+
+// Update replaces the vertices, keeping the layout and any indices, which
+// must stay within the new vertices. Data of the same size is written
+// into a freshly orphaned buffer: the driver hands over new storage
+// rather than wait for draws still reading the old, which pays off when
+// updating every frame. Orphaning only costs for a mesh updated rarely or
+// in small parts, which is better off static or written in place.
+func (m *Mesh) Update(vertices []float32) {
+	if m.vbos == nil {
+		panic("Mesh.Update called after Delete")
+	}
+	if m.stride == 0 {
+		panic("Mesh.Update called on a mesh of separate buffers")
+	}
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, m.vbos[0])
+	if size := len(vertices) * 4; size == m.size {
+		gl.BufferData(gl.ARRAY_BUFFER, size, nil, m.usage)
+		gl.BufferSubData(gl.ARRAY_BUFFER, 0, size, gl.Ptr(vertices))
+	} else {
+		// new storage of the new size is no worse than orphaning
+		m.size = size
+		gl.BufferData(gl.ARRAY_BUFFER, size, gl.Ptr(vertices), m.usage)
+	}
+
+	if m.ebo == 0 && m.stride > 0 {
+		m.count = int32(len(vertices) / m.stride)
+	}
+}
+
+// vao returns the current context's vertex array object for the mesh,
+// linking the buffers to the attribute locations
+func (m *Mesh) vao() uint32 {
+	context := glfw.GetCurrentContext()
+	if vao, ok := m.vaos[context]; ok {
+		return vao
+	}
+
+	var vao uint32
+	gl.GenVertexArrays(1, &vao)
+	gl.BindVertexArray(vao)
+
+	for _, attrib := range m.attribs {
+		gl.BindBuffer(gl.ARRAY_BUFFER, attrib.buffer)
+		gl.VertexAttribPointer(attrib.location, attrib.size, gl.FLOAT, false, int32(attrib.stride*4), gl.PtrOffset(attrib.offset*4))
+		gl.EnableVertexAttribArray(attrib.location)
+	}
+
+	// the element buffer binding is part of the vao's state
+	if m.ebo != 0 {
+		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, m.ebo)
+	}
+
+	m.vaos[context] = vao
+	return vao
+}
+
+// Draw draws the mesh's primitives with the bound program
+func (m *Mesh) Draw() {
+	if m.vbos == nil {
+		panic("Mesh.Draw called after Delete")
+	}
+
+	vao := m.vao()
+	gl.BindVertexArray(vao)
+
+	if m.ebo != 0 {
+		gl.DrawElements(m.Primitive, m.count, gl.UNSIGNED_INT, gl.PtrOffset(0))
+		capture.logf("draw elements: vao %v, %v indices", vao, m.count)
+	} else {
+		gl.DrawArrays(m.Primitive, 0, m.count)
+		capture.logf("draw arrays: vao %v, %v vertices", vao, m.count)
+	}
+}
+
+// Delete frees the buffers and the current context's vertex array
+// object, zeroing them so that a second Delete panics
+func (m *Mesh) Delete() {
+	if m.vbos == nil {
+		panic("Mesh.Delete called twice")
+	}
+
+	if vao, ok := m.vaos[glfw.GetCurrentContext()]; ok {
+		gl.DeleteVertexArrays(1, &vao)
+	}
+	m.vaos = nil
+
+	gl.DeleteBuffers(int32(len(m.vbos)), &m.vbos[0])
+	if m.ebo != 0 {
+		gl.DeleteBuffers(1, &m.ebo)
+	}
+	m.vbos, m.ebo = nil, 0
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSeparateLayout(t *testing.T) {
+	count, strides, err := separateLayout([]AttribBuffer{
+		{"position", 3, make([]float32, 3*5)},
+		{"normal", 3, make([]float32, 3*5)},
+		{"texCoord", 2, make([]float32, 2*5)},
+		{"weight", 1, make([]float32, 5)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 5 {
+		t.Errorf("counted %v vertices, want 5", count)
+	}
+	// tightly packed, so each stride is its attribute's size
+	if want := []int{3, 3, 2, 1}; !reflect.DeepEqual(strides, want) {
+		t.Errorf("strides %v, want %v", strides, want)
+	}
+
+	for _, test := range []struct {
+		name    string
+		buffers []AttribBuffer
+		err     string
+	}{
+		{"no buffers", nil, "no attribute buffers"},
+		{"zero size", []AttribBuffer{{"position", 0, nil}}, "attribute position has size 0, not 1 to 4"},
+		{"too large", []AttribBuffer{{"matrix", 16, make([]float32, 16)}}, "attribute matrix has size 16, not 1 to 4"},
+		{
+			"partial vertex",
+			[]AttribBuffer{{"position", 3, make([]float32, 7)}},
+			"attribute position has 7 values, not a whole number of vertices of 3",
+		},
+		{
+			"different counts",
+			[]AttribBuffer{{"position", 3, make([]float32, 3*4)}, {"texCoord", 2, make([]float32, 2*3)}},
+			"attribute texCoord has 3 vertices, but position has 4",
+		},
+	} {
+		if _, _, err := separateLayout(test.buffers); err == nil || err.Error() != test.err {
+			t.Errorf("%v: error %v, want %v", test.name, err, test.err)
+		}
+	}
+}
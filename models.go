@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"github.com/angus-g/gopengl/geometry"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// modelData is the indexed triangle data of a parsed model, with
+// vertices in the mesh vertex layout
+type modelData struct {
+	vertices []float32
+	indices  []uint32
+}
+
+// modelLibrary is a list of OBJ models, parsed the first time each is shown
+type modelLibrary struct {
+	paths   []string
+	current int
+	loaded  map[string]*modelData
+}
+
+// newModelLibrary opens a single model file, or every OBJ model in a
+// directory in name order. Without a path it holds the built-in shapes.
+func newModelLibrary(path string) (*modelLibrary, error) {
+	if path == "" {
+		sphere, indices := geometry.Sphere(16, 32)
+		return &modelLibrary{
+			paths: []string{"cube", "plane", "sphere"},
+			loaded: map[string]*modelData{
+				"cube":   {vertices: geometry.Cube()},
+				"plane":  {vertices: geometry.Plane()},
+				"sphere": {vertices: sphere, indices: indices},
+			},
+		}, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &modelLibrary{loaded: make(map[string]*modelData)}
+	if !info.IsDir() {
+		l.paths = []string{path}
+		return l, nil
+	}
+
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ".obj") {
+			l.paths = append(l.paths, filepath.Join(path, entry.Name()))
+		}
+	}
+	if len(l.paths) == 0 {
+		return nil, fmt.Errorf("no OBJ models in %v", path)
+	}
+	sort.Strings(l.paths)
+
+	return l, nil
+}
+
+// Name is the path of the current model, or the name of a built-in shape
+func (l *modelLibrary) Name() string {
+	return l.paths[l.current]
+}
+
+// Model parses the current model if it has not been shown before
+func (l *modelLibrary) Model() (*modelData, error) {
+	path := l.paths[l.current]
+	if m, ok := l.loaded[path]; ok {
+		return m, nil
+	}
+
+	m, err := loadOBJ(path)
+	if err != nil {
+		return nil, err
+	}
+	l.loaded[path] = m
+
+	return m, nil
+}
+
+// Step moves forwards or backwards through the models, wrapping around
+func (l *modelLibrary) Step(delta int) {
+	n := len(l.paths)
+	l.current = ((l.current+delta)%n + n) % n
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"github.com/angus-g/gopengl/math3d"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Node is part of a scene graph, placed relative to its parent. A node
+// can be shared by several parents to draw it at each of them.
+type Node struct {
+	Transform math3d.Transform
+
+	// drawn at the node if set, otherwise the node only groups children
+	Mesh     *Mesh
+	Children []*Node
+
+	// colors the node and the children that set none of their own
+	Material *Material
+}
+
+// NewNode returns an empty node at its parent's origin
+func NewNode() *Node {
+	return &Node{Transform: math3d.NewTransform()}
+}
+
+// Add appends children to the node
+func (n *Node) Add(children ...*Node) {
+	n.Children = append(n.Children, children...)
+}
+
+// WorldMatrix places the node in the world given its parent's world
+// matrix
+func (n *Node) WorldMatrix(parent mgl32.Mat4) mgl32.Mat4 {
+	return parent.Mul4(n.Transform.Matrix())
+}
+
+// Walk visits the node and its descendants depth first, parents before
+// children and children in order, with each one's world matrix given its
+// parent's and the material it is drawn in. Nodes without a material
+// anywhere above them get defaultMaterial.
+func (n *Node) Walk(parent mgl32.Mat4, visit func(node *Node, world mgl32.Mat4, material *Material)) {
+	n.walk(parent, &defaultMaterial, visit)
+}
+
+func (n *Node) walk(parent mgl32.Mat4, material *Material, visit func(*Node, mgl32.Mat4, *Material)) {
+	if n.Material != nil {
+		material = n.Material
+	}
+
+	world := n.WorldMatrix(parent)
+	visit(n, world, material)
+
+	for _, child := range n.Children {
+		child.walk(world, material, visit)
+	}
+}
+
+// Draw walks the tree, uploading each node's world matrix to the bound
+// program's model uniform and its material before drawing its mesh
+func (n *Node) Draw(program *Program, parent mgl32.Mat4) {
+	n.Walk(parent, func(node *Node, world mgl32.Mat4, material *Material) {
+		if node.Mesh == nil {
+			return
+		}
+		program.SetMat4("model", world)
+		material.upload(program)
+		node.Mesh.Draw()
+	})
+}
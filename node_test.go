@@ -0,0 +1,75 @@
+package main
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"testing"
+)
+
+// approxEqual reports whether a and b match element by element to within
+// a fixed tolerance, which mgl32's comparisons tighten near zero
+func approxEqual(a, b []float32) bool {
+	for i := range a {
+		if math.Abs(float64(a[i]-b[i])) > 1e-5 {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
+
+func TestNodeWalk(t *testing.T) {
+	// root
+	// ├── left, moved along x and holding a tinted leaf scaled by 2
+	// └── right, turned a quarter about z and sharing the same leaf
+	tint := &Material{Color: mgl32.Vec3{1.0, 0.0, 0.0}}
+	root := NewNode()
+	left := NewNode()
+	left.Transform.Position = mgl32.Vec3{1.0, 0.0, 0.0}
+	left.Material = tint
+	right := NewNode()
+	right.Transform.Rotation = mgl32.QuatRotate(mgl32.DegToRad(90.0), mgl32.Vec3{0.0, 0.0, 1.0})
+	leaf := NewNode()
+	leaf.Transform.Scale = mgl32.Vec3{2.0, 2.0, 2.0}
+	left.Add(leaf)
+	right.Add(leaf)
+	root.Add(left, right)
+
+	parent := mgl32.Translate3D(0.0, 0.0, 5.0)
+	want := []struct {
+		node     *Node
+		world    mgl32.Mat4
+		material *Material
+	}{
+		{root, parent, &defaultMaterial},
+		{left, parent.Mul4(mgl32.Translate3D(1.0, 0.0, 0.0)), tint},
+		{leaf, parent.Mul4(mgl32.Translate3D(1.0, 0.0, 0.0)).Mul4(mgl32.Scale3D(2.0, 2.0, 2.0)), tint},
+		{right, parent.Mul4(mgl32.HomogRotate3DZ(mgl32.DegToRad(90.0))), &defaultMaterial},
+		{leaf, parent.Mul4(mgl32.HomogRotate3DZ(mgl32.DegToRad(90.0))).Mul4(mgl32.Scale3D(2.0, 2.0, 2.0)), &defaultMaterial},
+	}
+
+	i := 0
+	root.Walk(parent, func(node *Node, world mgl32.Mat4, material *Material) {
+		if i >= len(want) {
+			t.Fatalf("visited more than %v nodes", len(want))
+		}
+		if node != want[i].node {
+			t.Errorf("visit %v is the wrong node", i)
+		}
+		if !approxEqual(world[:], want[i].world[:]) {
+			t.Errorf("visit %v has world matrix %v, want %v", i, world, want[i].world)
+		}
+		if material != want[i].material {
+			t.Errorf("visit %v has material %v, want %v", i, material.Color, want[i].material.Color)
+		}
+		i++
+	})
+	if i != len(want) {
+		t.Errorf("visited %v nodes, want %v", i, len(want))
+	}
+
+	// the leaf lands wherever each parent puts it
+	got := mgl32.TransformCoordinate(mgl32.Vec3{1.0, 0.0, 0.0}, want[4].world)
+	if !approxEqual(got[:], []float32{0.0, 2.0, 5.0}) {
+		t.Errorf("leaf under right moves 1, 0, 0 to %v, want 0, 2, 5", got)
+	}
+}
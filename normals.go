@@ -0,0 +1,40 @@
+package main
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// flatShaded unrolls a model's triangles so that no vertex is shared,
+// giving every vertex the normal of its face for a faceted look
+func flatShaded(m *modelData) *modelData {
+	indices := m.indices
+	if indices == nil {
+		indices = make([]uint32, len(m.vertices)/meshVertexSize)
+		for i := range indices {
+			indices[i] = uint32(i)
+		}
+	}
+	flat := &modelData{vertices: make([]float32, 0, len(indices)*meshVertexSize)}
+
+	vertex := func(i uint32) []float32 {
+		return m.vertices[int(i)*meshVertexSize : int(i+1)*meshVertexSize]
+	}
+	position := func(v []float32) mgl32.Vec3 {
+		return mgl32.Vec3{v[0], v[1], v[2]}
+	}
+
+	for i := 0; i+2 < len(indices); i += 3 {
+		a, b, c := vertex(indices[i]), vertex(indices[i+1]), vertex(indices[i+2])
+
+		n := position(b).Sub(position(a)).Cross(position(c).Sub(position(a)))
+		if n.Len() > 0.0 {
+			n = n.Normalize()
+		}
+
+		for _, v := range [][]float32{a, b, c} {
+			flat.vertices = append(flat.vertices, v[0], v[1], v[2], n[0], n[1], n[2], v[6], v[7])
+		}
+	}
+
+	return flat
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/go-gl/mathgl/mgl32"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LoadOBJ reads a Wavefront OBJ model into a mesh drawn with program
+func LoadOBJ(path string, program *Program) (*Mesh, error) {
+	m, err := loadOBJ(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMesh(program, m.vertices, m.indices, meshAttribs), nil
+}
+
+// loadOBJ parses an OBJ file without touching the GPU
+func loadOBJ(path string) (*modelData, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseOBJ(f, path)
+}
+
+// parseOBJ reads the positions, texture coordinates, normals and faces
+// of an OBJ model, ignoring everything else. Faces are fan triangulated,
+// and each distinct v/vt/vn combination becomes one indexed vertex.
+// Missing texture coordinates are (0, 0), and faces without normals get
+// the normal of their plane.
+func parseOBJ(r io.Reader, name string) (*modelData, error) {
+	var positions, normals []mgl32.Vec3
+	var texCoords []mgl32.Vec2
+
+	m := &modelData{}
+	shared := map[[3]int]uint32{}
+	faces := 0
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		fail := func(format string, args ...interface{}) error {
+			return fmt.Errorf("%v:%v: %v", name, line, fmt.Sprintf(format, args...))
+		}
+
+		floats := func(want int) ([]float32, error) {
+			if len(fields)-1 < want {
+				return nil, fail("%v needs %v values", fields[0], want)
+			}
+			values := make([]float32, want)
+			for i := range values {
+				v, err := strconv.ParseFloat(fields[i+1], 32)
+				if err != nil {
+					return nil, fail("bad number %q", fields[i+1])
+				}
+				values[i] = float32(v)
+			}
+			return values, nil
+		}
+
+		switch fields[0] {
+		case "v":
+			v, err := floats(3)
+			if err != nil {
+				return nil, err
+			}
+			positions = append(positions, mgl32.Vec3{v[0], v[1], v[2]})
+
+		case "vt":
+			// v is optional for 1D textures
+			v, err := floats(1)
+			if err != nil {
+				return nil, err
+			}
+			uv := mgl32.Vec2{v[0], 0.0}
+			if len(fields) > 2 {
+				if v, err = floats(2); err != nil {
+					return nil, err
+				}
+				uv[1] = v[1]
+			}
+			texCoords = append(texCoords, uv)
+
+		case "vn":
+			v, err := floats(3)
+			if err != nil {
+				return nil, err
+			}
+			normals = append(normals, mgl32.Vec3{v[0], v[1], v[2]})
+
+		case "f":
+			if len(fields) < 4 {
+				return nil, fail("face needs at least 3 vertices")
+			}
+
+			// indices count from 1, or back from the latest when negative
+			resolve := func(s string, count int) (int, error) {
+				if s == "" {
+					return -1, nil
+				}
+				i, err := strconv.Atoi(s)
+				if err == nil && i < 0 {
+					i += count
+				} else if err == nil {
+					i--
+				}
+				if err != nil || i < 0 || i >= count {
+					return 0, fail("bad index %q", s)
+				}
+				return i, nil
+			}
+
+			corners := make([][3]int, len(fields)-1)
+			hasNormals := true
+			for i, field := range fields[1:] {
+				parts := strings.Split(field, "/")
+				if len(parts) > 3 || parts[0] == "" {
+					return nil, fail("bad face vertex %q", field)
+				}
+				for len(parts) < 3 {
+					parts = append(parts, "")
+				}
+
+				var err error
+				if corners[i][0], err = resolve(parts[0], len(positions)); err != nil {
+					return nil, err
+				}
+				if corners[i][1], err = resolve(parts[1], len(texCoords)); err != nil {
+					return nil, err
+				}
+				if corners[i][2], err = resolve(parts[2], len(normals)); err != nil {
+					return nil, err
+				}
+				hasNormals = hasNormals && corners[i][2] >= 0
+			}
+
+			// corners without normals are not shared with other faces
+			var plane mgl32.Vec3
+			if !hasNormals {
+				a, b, c := positions[corners[0][0]], positions[corners[1][0]], positions[corners[2][0]]
+				if plane = b.Sub(a).Cross(c.Sub(a)); plane.Len() > 0.0 {
+					plane = plane.Normalize()
+				}
+				for i := range corners {
+					corners[i][2] = -2 - faces
+				}
+			}
+			faces++
+
+			index := func(c [3]int) uint32 {
+				if i, ok := shared[c]; ok {
+					return i
+				}
+
+				pos, norm, uv := positions[c[0]], plane, mgl32.Vec2{}
+				if c[1] >= 0 {
+					uv = texCoords[c[1]]
+				}
+				if c[2] >= 0 {
+					norm = normals[c[2]]
+				}
+
+				i := uint32(len(m.vertices) / meshVertexSize)
+				m.vertices = append(m.vertices, pos[0], pos[1], pos[2], norm[0], norm[1], norm[2], uv[0], uv[1])
+				shared[c] = i
+				return i
+			}
+
+			for i := 1; i+1 < len(corners); i++ {
+				m.indices = append(m.indices, index(corners[0]), index(corners[i]), index(corners[i+1]))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %v: %v", name, err)
+	}
+
+	return m, nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseOBJ(t *testing.T) {
+	// a unit square's corners counter-clockwise about +z, for faces to
+	// pick from
+	const square = "v 0 0 0\nv 1 0 0\nv 1 1 0\nv 0 1 0\n"
+
+	for _, test := range []struct {
+		name     string
+		obj      string
+		vertices []float32
+		indices  []uint32
+	}{
+		{
+			name: "triangle with everything",
+			obj:  square + "vt 0.5 0.25\nvn 0 0 1\nf 1/1/1 2/1/1 3/1/1\n",
+			vertices: []float32{
+				0, 0, 0, 0, 0, 1, 0.5, 0.25,
+				1, 0, 0, 0, 0, 1, 0.5, 0.25,
+				1, 1, 0, 0, 0, 1, 0.5, 0.25,
+			},
+			indices: []uint32{0, 1, 2},
+		},
+		{
+			name: "negative indices count back from the latest",
+			obj:  square + "vn 0 0 1\nf -4//-1 -3//-1 -2//-1\n",
+			vertices: []float32{
+				0, 0, 0, 0, 0, 1, 0, 0,
+				1, 0, 0, 0, 0, 1, 0, 0,
+				1, 1, 0, 0, 0, 1, 0, 0,
+			},
+			indices: []uint32{0, 1, 2},
+		},
+		{
+			name: "quads are fanned from their first corner",
+			obj:  square + "vn 0 0 1\nf 1//1 2//1 3//1 4//1\n",
+			vertices: []float32{
+				0, 0, 0, 0, 0, 1, 0, 0,
+				1, 0, 0, 0, 0, 1, 0, 0,
+				1, 1, 0, 0, 0, 1, 0, 0,
+				0, 1, 0, 0, 0, 1, 0, 0,
+			},
+			indices: []uint32{0, 1, 2, 0, 2, 3},
+		},
+		{
+			name: "n-gons are fanned from their first corner",
+			obj:  square + "v -1 0.5 0\nvn 0 0 1\nf 1//1 2//1 3//1 4//1 5//1\n",
+			vertices: []float32{
+				0, 0, 0, 0, 0, 1, 0, 0,
+				1, 0, 0, 0, 0, 1, 0, 0,
+				1, 1, 0, 0, 0, 1, 0, 0,
+				0, 1, 0, 0, 0, 1, 0, 0,
+				-1, 0.5, 0, 0, 0, 1, 0, 0,
+			},
+			indices: []uint32{0, 1, 2, 0, 2, 3, 0, 3, 4},
+		},
+		{
+			name: "missing texture coordinates are zero",
+			obj:  square + "vt 0.5 0.5\nvn 1 0 0\nf 1//1 2/1/1 3//1\n",
+			vertices: []float32{
+				0, 0, 0, 1, 0, 0, 0, 0,
+				1, 0, 0, 1, 0, 0, 0.5, 0.5,
+				1, 1, 0, 1, 0, 0, 0, 0,
+			},
+			indices: []uint32{0, 1, 2},
+		},
+		{
+			name: "missing normals are the plane's",
+			obj:  square + "f 1 3 2\n",
+			vertices: []float32{
+				0, 0, 0, 0, 0, -1, 0, 0,
+				1, 1, 0, 0, 0, -1, 0, 0,
+				1, 0, 0, 0, 0, -1, 0, 0,
+			},
+			indices: []uint32{0, 1, 2},
+		},
+		{
+			name: "repeated triples are one vertex",
+			obj:  square + "vn 0 0 1\nf 1//1 2//1 3//1\nf 1//1 3//1 4//1\n",
+			vertices: []float32{
+				0, 0, 0, 0, 0, 1, 0, 0,
+				1, 0, 0, 0, 0, 1, 0, 0,
+				1, 1, 0, 0, 0, 1, 0, 0,
+				0, 1, 0, 0, 0, 1, 0, 0,
+			},
+			indices: []uint32{0, 1, 2, 0, 2, 3},
+		},
+		{
+			name: "faces without normals share no vertices",
+			obj:  square + "f 1 2 3\nf 1 3 4\n",
+			vertices: []float32{
+				0, 0, 0, 0, 0, 1, 0, 0,
+				1, 0, 0, 0, 0, 1, 0, 0,
+				1, 1, 0, 0, 0, 1, 0, 0,
+				0, 0, 0, 0, 0, 1, 0, 0,
+				1, 1, 0, 0, 0, 1, 0, 0,
+				0, 1, 0, 0, 0, 1, 0, 0,
+			},
+			indices: []uint32{0, 1, 2, 3, 4, 5},
+		},
+		{
+			name: "comments and other statements are skipped",
+			obj:  "# a triangle\no tri\n" + square + "s off\nusemtl none\nf 1 2 3\n",
+			vertices: []float32{
+				0, 0, 0, 0, 0, 1, 0, 0,
+				1, 0, 0, 0, 0, 1, 0, 0,
+				1, 1, 0, 0, 0, 1, 0, 0,
+			},
+			indices: []uint32{0, 1, 2},
+		},
+	} {
+		m, err := parseOBJ(strings.NewReader(test.obj), "test.obj")
+		if err != nil {
+			t.Errorf("%v: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(m.vertices, test.vertices) {
+			t.Errorf("%v: vertices %v, want %v", test.name, m.vertices, test.vertices)
+		}
+		if !reflect.DeepEqual(m.indices, test.indices) {
+			t.Errorf("%v: indices %v, want %v", test.name, m.indices, test.indices)
+		}
+	}
+}
+
+func TestParseOBJErrors(t *testing.T) {
+	const square = "v 0 0 0\nv 1 0 0\nv 1 1 0\nv 0 1 0\n"
+
+	for _, test := range []struct {
+		name string
+		obj  string
+		err  string
+	}{
+		{"position past the end", square + "f 1 2 5\n", `test.obj:5: bad index "5"`},
+		{"position zero", square + "f 0 1 2\n", `test.obj:5: bad index "0"`},
+		{"negative past the start", square + "f -5 1 2\n", `test.obj:5: bad index "-5"`},
+		{"texture coordinate past the end", square + "vt 0 0\nf 1/2 2/1 3/1\n", `test.obj:6: bad index "2"`},
+		{"normal past the end", square + "f 1//1 2//1 3//1\n", `test.obj:5: bad index "1"`},
+		{"index not a number", square + "f 1 two 3\n", `test.obj:5: bad index "two"`},
+		{"no position", square + "f /1 2 3\n", `test.obj:5: bad face vertex "/1"`},
+		{"too many parts", square + "f 1/1/1/1 2 3\n", `test.obj:5: bad face vertex "1/1/1/1"`},
+		{"too few corners", square + "f 1 2\n", "test.obj:5: face needs at least 3 vertices"},
+		{"short position", "v 0 0\n", "test.obj:1: v needs 3 values"},
+		{"bad number", "v 0 zero 0\n", `test.obj:1: bad number "zero"`},
+	} {
+		_, err := parseOBJ(strings.NewReader(test.obj), "test.obj")
+		if err == nil || err.Error() != test.err {
+			t.Errorf("%v: error %v, want %v", test.name, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"github.com/angus-g/gopengl/math3d"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+)
+
+// OrbitCamera circles a target point for inspecting a model: dragging
+// with the left button turns it around the target, the right button pans
+// the target across the screen and scrolling zooms in and out. Angles
+// are in degrees, with azimuth about the z axis and elevation towards it.
+type OrbitCamera struct {
+	Target    mgl32.Vec3
+	Radius    float32
+	Azimuth   float32
+	Elevation float32
+
+	// zoom stays within these distances of the target
+	MinRadius, MaxRadius float32
+
+	// degrees per pixel of drag
+	Sensitivity float32
+
+	// whether mouse input moves the camera, so that another camera can
+	// take over the window
+	Enabled bool
+
+	// cursor position at the last movement, and what the held button does
+	lastX, lastY     float64
+	turning, panning bool
+}
+
+// NewOrbitCamera places a camera at eye circling target
+func NewOrbitCamera(eye, target mgl32.Vec3) *OrbitCamera {
+	o := &OrbitCamera{
+		MinRadius:   0.1,
+		MaxRadius:   100.0,
+		Sensitivity: 0.5,
+		Enabled:     true,
+	}
+	o.LookAt(eye, target)
+
+	return o
+}
+
+// LookAt moves the camera to eye, circling target from there on
+func (o *OrbitCamera) LookAt(eye, target mgl32.Vec3) {
+	o.Target = target
+
+	offset := eye.Sub(target)
+	o.Radius = offset.Len()
+	if o.Radius == 0.0 {
+		offset, o.Radius = mgl32.Vec3{1.0, 0.0, 0.0}, 1.0
+	}
+	o.Azimuth, o.Elevation = math3d.YawPitch(offset)
+}
+
+// Eye is the camera's position
+func (o *OrbitCamera) Eye() mgl32.Vec3 {
+	return o.Target.Add(math3d.Direction(o.Azimuth, o.Elevation).Mul(o.Radius))
+}
+
+// ViewMatrix looks from the eye at the target with z up
+func (o *OrbitCamera) ViewMatrix() mgl32.Mat4 {
+	return math3d.LookAt(o.Eye(), o.Target)
+}
+
+// Attach steers the camera with a window's mouse while it is enabled
+func (o *OrbitCamera) Attach(window *glfw.Window) {
+	in := InputFor(window)
+
+	in.RegisterMouseButtonHandler(func(button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) bool {
+		if !o.Enabled {
+			return false
+		}
+
+		held := action == glfw.Press
+		switch button {
+		case glfw.MouseButtonLeft:
+			o.turning = held
+		case glfw.MouseButtonRight:
+			o.panning = held
+		default:
+			return false
+		}
+		o.lastX, o.lastY = window.GetCursorPos()
+
+		return true
+	})
+
+	in.RegisterCursorPosHandler(func(x, y float64) bool {
+		if !o.Enabled || (!o.turning && !o.panning) {
+			return false
+		}
+
+		dx, dy := float32(x-o.lastX), float32(y-o.lastY)
+		o.lastX, o.lastY = x, y
+
+		if o.turning {
+			// dragging right carries the model round to the right, and
+			// dragging down tips its top towards the eye
+			o.Azimuth -= dx * o.Sensitivity
+			o.Elevation += dy * o.Sensitivity
+
+			// keep short of the poles, where the view would flip
+			if o.Elevation > 89.0 {
+				o.Elevation = 89.0
+			} else if o.Elevation < -89.0 {
+				o.Elevation = -89.0
+			}
+		}
+
+		if o.panning {
+			// the target follows the cursor at a rate that looks about
+			// right at any distance
+			forward := o.Target.Sub(o.Eye()).Normalize()
+			right := forward.Cross(mgl32.Vec3{0.0, 0.0, 1.0}).Normalize()
+			up := right.Cross(forward)
+
+			step := o.Radius * 0.002
+			o.Target = o.Target.Sub(right.Mul(dx * step)).Add(up.Mul(dy * step))
+		}
+
+		return true
+	})
+
+	in.RegisterScrollHandler(func(xoff, yoff float64) bool {
+		if !o.Enabled {
+			return false
+		}
+
+		// each notch moves a tenth of the way in, or back out
+		o.Radius *= float32(math.Pow(0.9, yoff))
+		if o.Radius < o.MinRadius {
+			o.Radius = o.MinRadius
+		} else if o.Radius > o.MaxRadius {
+			o.Radius = o.MaxRadius
+		}
+
+		return true
+	})
+}
@@ -0,0 +1,15 @@
+#version 150
+
+in vec3 position;
+
+uniform mat4 model;
+uniform mat4 view;
+uniform mat4 proj;
+
+// the outline is the model grown about its center by this factor
+uniform float scale;
+uniform vec3 center;
+
+void main() {
+    gl_Position = proj * view * model * vec4(center + (position - center) * scale, 1.0);
+}
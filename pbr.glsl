@@ -0,0 +1,86 @@
+#version 150
+
+in vec3 vertNorm;
+in vec3 fragPos;
+
+uniform vec3 lightDir;
+uniform vec3 lightCol;
+uniform vec3 cameraPos;
+
+// metallic-roughness material
+uniform vec3 albedo;
+uniform float metallic;
+uniform float roughness;
+uniform float ao;
+
+// tint of the object being drawn, white to leave albedo as it is
+uniform vec3 materialColor;
+
+// set with -srgb, when the framebuffer gamma encodes what is written
+uniform bool framebufferSRGB;
+
+out vec4 outColor;
+
+const float PI = 3.14159265359;
+
+// GGX/Trowbridge-Reitz normal distribution
+float distributionGGX(vec3 N, vec3 H, float rough) {
+    float a = rough * rough;
+    float a2 = a * a;
+    float NdotH = max(dot(N, H), 0.0);
+    float denom = NdotH * NdotH * (a2 - 1.0) + 1.0;
+
+    return a2 / (PI * denom * denom);
+}
+
+// Schlick-GGX geometry term for a single direction
+float geometrySchlickGGX(float NdotV, float rough) {
+    float r = rough + 1.0;
+    float k = (r * r) / 8.0;
+
+    return NdotV / (NdotV * (1.0 - k) + k);
+}
+
+// Smith's method combining view and light occlusion
+float geometrySmith(vec3 N, vec3 V, vec3 L, float rough) {
+    return geometrySchlickGGX(max(dot(N, V), 0.0), rough) *
+        geometrySchlickGGX(max(dot(N, L), 0.0), rough);
+}
+
+vec3 fresnelSchlick(float cosTheta, vec3 F0) {
+    return F0 + (1.0 - F0) * pow(1.0 - cosTheta, 5.0);
+}
+
+void main() {
+    vec3 N = normalize(vertNorm);
+    vec3 V = normalize(cameraPos - fragPos);
+    vec3 L = -normalize(lightDir);
+    vec3 H = normalize(V + L);
+    vec3 base = albedo * materialColor;
+
+    // dielectrics reflect ~4%, metals tint reflection by their color
+    vec3 F0 = mix(vec3(0.04), base, metallic);
+
+    // Cook-Torrance specular BRDF
+    float NDF = distributionGGX(N, H, roughness);
+    float G = geometrySmith(N, V, L, roughness);
+    vec3 F = fresnelSchlick(max(dot(H, V), 0.0), F0);
+    vec3 specular = NDF * G * F /
+        (4.0 * max(dot(N, V), 0.0) * max(dot(N, L), 0.0) + 0.0001);
+
+    // energy not reflected is refracted, metals have no diffuse
+    vec3 kD = (vec3(1.0) - F) * (1.0 - metallic);
+    float NdotL = max(dot(N, L), 0.0);
+    vec3 Lo = (kD * base / PI + specular) * lightCol * NdotL;
+
+    vec3 color = vec3(0.03) * base * ao + Lo;
+
+    // reinhard tone mapping, and gamma correction unless the framebuffer
+    // does it
+    color = color / (color + vec3(1.0));
+    if (!framebufferSRGB) {
+        color = pow(color, vec3(1.0 / 2.2));
+    }
+
+    outColor = vec4(color, 1.0);
+}
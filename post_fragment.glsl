@@ -0,0 +1,35 @@
+#version 150
+
+in vec2 fragTexCoord;
+
+uniform sampler2D tex;
+
+// index into postEffects, with 0 copying the image unchanged
+uniform int effect;
+
+// set with -srgb, when the framebuffer gamma encodes what is written
+uniform bool framebufferSRGB;
+
+out vec4 outColor;
+
+void main() {
+    vec4 color = texture(tex, fragTexCoord);
+
+    switch (effect) {
+    case 1:
+        // perceived brightness of linear rgb
+        color.rgb = vec3(dot(color.rgb, vec3(0.2126, 0.7152, 0.0722)));
+        break;
+    case 2:
+        color.rgb = 1.0 - color.rgb;
+        break;
+    case 3:
+        // encoding twice would wash the image out
+        if (!framebufferSRGB) {
+            color.rgb = pow(color.rgb, vec3(1.0 / 2.2));
+        }
+        break;
+    }
+
+    outColor = color;
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"io/fs"
+	"strings"
+)
+
+// Program is a linked shader program that remembers the location of
+// every uniform it has been asked for
+type Program struct {
+	ID uint32
+
+	shaders  []uint32
+	uniforms map[string]int32
+}
+
+// NewProgram compiles and links a vertex and fragment shader file read
+// from fsys
+func NewProgram(fsys fs.FS, vertexShaderFile, fragmentShaderFile string) (*Program, error) {
+	// create shaders
+	vertexShader, err := compileShader(fsys, vertexShaderFile, gl.VERTEX_SHADER)
+	if err != nil {
+		return nil, err
+	}
+
+	fragmentShader, err := compileShader(fsys, fragmentShaderFile, gl.FRAGMENT_SHADER)
+	if err != nil {
+		gl.DeleteShader(vertexShader)
+		return nil, err
+	}
+
+	return linkProgram(vertexShader, fragmentShader)
+}
+
+// NewProgramFromSource links a vertex shader file with fragment shader
+// source held in memory
+func NewProgramFromSource(fsys fs.FS, vertexShaderFile, fragmentSource string) (*Program, error) {
+	if strings.TrimSpace(fragmentSource) == "" {
+		return nil, fmt.Errorf("no fragment shader source")
+	}
+
+	vertexShader, err := compileShader(fsys, vertexShaderFile, gl.VERTEX_SHADER)
+	if err != nil {
+		return nil, err
+	}
+
+	fragmentShader, err := compileShaderSource(fragmentSource, "fragment source", gl.FRAGMENT_SHADER)
+	if err != nil {
+		gl.DeleteShader(vertexShader)
+		return nil, err
+	}
+
+	return linkProgram(vertexShader, fragmentShader)
+}
+
+// linkProgram links compiled shaders into a program, which takes over
+// the shaders. Vertex attributes are bound to fixed locations so that any
+// program can be drawn with the same vertex array objects.
+func linkProgram(shaders ...uint32) (*Program, error) {
+	p := &Program{
+		ID:       gl.CreateProgram(),
+		shaders:  shaders,
+		uniforms: map[string]int32{},
+	}
+	for _, shader := range shaders {
+		gl.AttachShader(p.ID, shader)
+	}
+	gl.BindAttribLocation(p.ID, 0, gl.Str("position\x00"))
+	gl.BindAttribLocation(p.ID, 1, gl.Str("normal\x00"))
+	gl.BindAttribLocation(p.ID, 2, gl.Str("texCoord\x00"))
+	gl.LinkProgram(p.ID)
+
+	// error handling
+	var status int32
+	gl.GetProgramiv(p.ID, gl.LINK_STATUS, &status)
+	if status == gl.FALSE {
+		var logLength int32
+		gl.GetProgramiv(p.ID, gl.INFO_LOG_LENGTH, &logLength)
+
+		log := strings.Repeat("\x00", int(logLength+1))
+		gl.GetProgramInfoLog(p.ID, logLength, nil, gl.Str(log))
+		p.Delete()
+
+		return nil, fmt.Errorf("failed to link program: %v", log)
+	}
+
+	return p, nil
+}
+
+// Use binds the program for drawing
+func (p *Program) Use() {
+	if p.ID == 0 {
+		panic("Program.Use called after Delete")
+	}
+	gl.UseProgram(p.ID)
+}
+
+// Uniform returns the location of a uniform, looking it up the first time
+// it is asked for. Uniforms the program does not declare are -1, which GL
+// silently ignores when set.
+func (p *Program) Uniform(name string) int32 {
+	if loc, ok := p.uniforms[name]; ok {
+		return loc
+	}
+
+	loc := gl.GetUniformLocation(p.ID, gl.Str(name+"\x00"))
+	p.uniforms[name] = loc
+	return loc
+}
+
+// SetMat4 sets a mat4 uniform of the bound program, doing nothing if the
+// program has no such uniform
+func (p *Program) SetMat4(name string, m mgl32.Mat4) {
+	if loc := p.Uniform(name); loc >= 0 {
+		gl.UniformMatrix4fv(loc, 1, false, &m[0])
+		capture.logf("uniform %v = %v", name, m)
+	}
+}
+
+// SetVec2 sets a vec2 uniform of the bound program
+func (p *Program) SetVec2(name string, v mgl32.Vec2) {
+	if loc := p.Uniform(name); loc >= 0 {
+		gl.Uniform2fv(loc, 1, &v[0])
+		capture.logf("uniform %v = %v", name, v)
+	}
+}
+
+// SetVec3 sets a vec3 uniform of the bound program
+func (p *Program) SetVec3(name string, v mgl32.Vec3) {
+	if loc := p.Uniform(name); loc >= 0 {
+		gl.Uniform3fv(loc, 1, &v[0])
+		capture.logf("uniform %v = %v", name, v)
+	}
+}
+
+// SetFloat sets a float uniform of the bound program
+func (p *Program) SetFloat(name string, f float32) {
+	if loc := p.Uniform(name); loc >= 0 {
+		gl.Uniform1f(loc, f)
+		capture.logf("uniform %v = %v", name, f)
+	}
+}
+
+// SetInt sets an int, bool or sampler uniform of the bound program
+func (p *Program) SetInt(name string, i int32) {
+	if loc := p.Uniform(name); loc >= 0 {
+		gl.Uniform1i(loc, i)
+		capture.logf("uniform %v = %v", name, i)
+	}
+}
+
+// Delete frees the program along with any shaders still attached to it.
+// Handles are zeroed so that using or deleting it again panics rather
+// than touching whatever GL hands the name to next.
+func (p *Program) Delete() {
+	if p.ID == 0 {
+		panic("Program.Delete called twice")
+	}
+
+	for _, shader := range p.shaders {
+		gl.DetachShader(p.ID, shader)
+		gl.DeleteShader(shader)
+	}
+	p.shaders = nil
+
+	gl.DeleteProgram(p.ID)
+	p.ID = 0
+	p.uniforms = nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"github.com/angus-g/gopengl/math3d"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// depthMode selects how view distance is mapped to the depth buffer
+type depthMode int
+
+const (
+	// depthStandard is OpenGL's default [-1, 1] depth range, which spends
+	// most of its precision close to the near plane
+	depthStandard depthMode = iota
+	// depthReversed maps the far plane to 0 and the near plane to 1 in a
+	// [0, 1] range, so that float precision offsets the perspective divide
+	depthReversed
+	// depthLogarithmic rewrites depth in the vertex shader, for contexts
+	// without clip control
+	depthLogarithmic
+)
+
+// projection holds the perspective parameters shared by all views
+type projection struct {
+	// vertical field of view in degrees
+	FOV   float32
+	Near  float32
+	Far   float32
+	Depth depthMode
+
+	// parallel projection, covering what the perspective one would at
+	// the focus distance
+	Ortho bool
+}
+
+// limits of the field of view in degrees that -fov accepts, short of the
+// 180 where the projection degenerates
+const (
+	minFOV = 1.0
+	maxFOV = 179.0
+)
+
+// scrolling zooms between these, or up to a wider -fov, before the view
+// turns fisheye
+const (
+	minZoomFOV = 1.0
+	maxZoomFOV = 60.0
+)
+
+// validate rejects parameters that produce a degenerate projection
+func (p *projection) validate() error {
+	if p.FOV < minFOV || p.FOV > maxFOV {
+		return fmt.Errorf("field of view %v must be between %v and %v degrees", p.FOV, minFOV, maxFOV)
+	}
+	if p.Near <= 0.0 {
+		return fmt.Errorf("near plane %v must be positive", p.Near)
+	}
+	if p.Far <= p.Near {
+		return fmt.Errorf("far plane %v must lie beyond the near plane %v", p.Far, p.Near)
+	}
+
+	return nil
+}
+
+// Matrix builds the projection for a viewport of the given aspect ratio,
+// looking at something focus away
+func (p *projection) Matrix(aspect, focus float32) mgl32.Mat4 {
+	reversed := p.Depth == depthReversed
+	switch {
+	case p.Ortho:
+		return math3d.FocusOrtho(p.FOV, aspect, focus, p.Near, p.Far, reversed)
+	case reversed:
+		return math3d.ReversedPerspective(p.FOV, aspect, p.Near, p.Far)
+	}
+	return mgl32.Perspective(mgl32.DegToRad(p.FOV), aspect, p.Near, p.Far)
+}
+
+// apply sets the depth range of the current context for the depth mode
+func (p *projection) apply() {
+	if p.Depth == depthReversed {
+		gl.ClipControl(gl.LOWER_LEFT, gl.ZERO_TO_ONE)
+		gl.ClearDepth(0.0)
+	}
+}
+
+// depthFunc is the depth test that keeps the nearer fragment
+func (p *projection) depthFunc() uint32 {
+	if p.Depth == depthReversed {
+		return gl.GREATER
+	}
+	return gl.LESS
+}
+
+// upload sets the depth uniforms of vertex.glsl on the bound program
+func (p *projection) upload(program *Program) {
+	// the logarithm needs a perspective w, and parallel depth is linear
+	// enough without it
+	program.SetInt("logDepth", boolToInt(p.Depth == depthLogarithmic && !p.Ortho))
+	program.SetFloat("farPlane", p.Far)
+}
+
+// offsetSign is the direction of polygon offset that pushes surfaces
+// away from the camera
+func (p *projection) offsetSign() float32 {
+	if p.Depth == depthReversed {
+		return -1.0
+	}
+	return 1.0
+}
+
+// hasExtension checks the current context's extension list
+func hasExtension(name string) bool {
+	var count int32
+	gl.GetIntegerv(gl.NUM_EXTENSIONS, &count)
+	for i := uint32(0); i < uint32(count); i++ {
+		if gl.GoStr(gl.GetStringi(gl.EXTENSIONS, i)) == name {
+			return true
+		}
+	}
+
+	return false
+}
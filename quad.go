@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"io/fs"
+)
+
+// two triangles covering normalised device coordinates, with texture
+// coordinates running over the whole screen
+var quadVertices = []float32{
+	-1, -1, 0, 0,
+	1, -1, 1, 0,
+	1, 1, 1, 1,
+	-1, -1, 0, 0,
+	1, 1, 1, 1,
+	-1, 1, 0, 1,
+}
+
+var quadAttribs = []VertexAttrib{
+	{"position", 2, 0},
+	{"texCoord", 2, 2},
+}
+
+// the quad replaces whatever is on screen, whichever way it faces
+var screenState = RenderState{}
+
+// postEffects names the effects of post_fragment.glsl by their index
+var postEffects = []string{"none", "grayscale", "invert", "gamma"}
+
+// ScreenQuad fills the viewport with a texture run through a fragment
+// shader, for showing and processing offscreen images
+type ScreenQuad struct {
+	Program *Program
+	mesh    *Mesh
+}
+
+// NewScreenQuad builds a quad drawn with the given fragment shader, which
+// samples tex at fragTexCoord
+func NewScreenQuad(fsys fs.FS, fragmentShader string) (*ScreenQuad, error) {
+	program, err := NewProgram(fsys, "screen_vertex.glsl", fragmentShader)
+	if err != nil {
+		return nil, err
+	}
+	program.Use()
+	program.SetInt("tex", 0)
+
+	return &ScreenQuad{Program: program, mesh: NewMesh(program, quadVertices, nil, quadAttribs)}, nil
+}
+
+// Draw covers the viewport with a 2D texture
+func (q *ScreenQuad) Draw(texture uint32) {
+	screenState.Apply()
+	q.Program.Use()
+
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, texture)
+	q.mesh.Draw()
+}
+
+// Delete frees the quad and its program
+func (q *ScreenQuad) Delete() {
+	q.mesh.Delete()
+	q.Program.Delete()
+}
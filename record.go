@@ -0,0 +1,82 @@
+package main
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"math"
+	"os"
+)
+
+// GIF delays are in hundredths of a second and viewers slow down
+// anything much faster, so frames are sampled at 25 a second whatever
+// the render rate
+const gifInterval = 0.04
+
+// gifRecorder samples a window's frames for a few seconds into a looping
+// GIF
+type gifRecorder struct {
+	path     string
+	duration float64
+
+	// times of the first and the latest sampled frame
+	start, last float64
+	anim        gif.GIF
+}
+
+func newGIFRecorder(path string, seconds float64) *gifRecorder {
+	return &gifRecorder{path: path, duration: seconds, start: -1.0}
+}
+
+// frame samples the window's back buffer if a frame is due, writing the
+// GIF and reporting true once the duration has passed. The window's
+// context must be current, and it must be called before the buffers are
+// swapped.
+func (r *gifRecorder) frame(window *glfw.Window, now float64) (bool, error) {
+	if r.start < 0.0 {
+		r.start = now
+	} else if now-r.last < gifInterval {
+		return false, nil
+	}
+
+	if now-r.start >= r.duration {
+		return true, r.write()
+	}
+
+	// each frame lasts until the next was actually sampled
+	if n := len(r.anim.Delay); n > 0 {
+		r.anim.Delay[n-1] = centiseconds(now - r.last)
+	}
+	r.last = now
+
+	// dithering hides most of the banding of a fixed palette
+	img := readBackBuffer(window)
+	frame := image.NewPaletted(img.Bounds(), palette.Plan9)
+	draw.FloydSteinberg.Draw(frame, img.Bounds(), img, image.Point{})
+
+	r.anim.Image = append(r.anim.Image, frame)
+	r.anim.Delay = append(r.anim.Delay, centiseconds(gifInterval))
+
+	return false, nil
+}
+
+// write saves the sampled frames, looping forever
+func (r *gifRecorder) write() error {
+	f, err := os.Create(r.path)
+	if err != nil {
+		return err
+	}
+	if err := gif.EncodeAll(f, &r.anim); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// centiseconds rounds seconds to a GIF frame delay
+func centiseconds(seconds float64) int {
+	return int(math.Round(seconds * 100.0))
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// RenderState is the fixed-function state a pass draws with. Passes
+// apply their own state rather than undoing each other's, so a pass that
+// changes it need not put it back.
+type RenderState struct {
+	DepthTest bool
+	DepthFunc uint32
+
+	// write the depth of drawn fragments, which transparent geometry
+	// usually should not so that what is behind it still gets drawn.
+	// Depth is tested either way.
+	DepthWrite bool
+
+	// face to cull, gl.BACK for closed models, or 0 to draw both sides
+	CullFace uint32
+
+	// mix fragments into the framebuffer by their alpha
+	Blend bool
+
+	Stencil StencilState
+
+	ClearColor mgl32.Vec4
+}
+
+// StencilState tests fragments against the stencil buffer and marks what
+// gets drawn. The zero value leaves the stencil test off.
+type StencilState struct {
+	// test comparing Ref with the stored value, or 0 for no test
+	Func uint32
+	Ref  int32
+
+	// what to store where fragments pass, or 0 to keep the stored value
+	Pass uint32
+}
+
+// Apply sets the state on the current context
+func (s RenderState) Apply() {
+	enable := func(cap uint32, on bool) {
+		if on {
+			gl.Enable(cap)
+		} else {
+			gl.Disable(cap)
+		}
+	}
+
+	enable(gl.DEPTH_TEST, s.DepthTest)
+	if s.DepthFunc != 0 {
+		gl.DepthFunc(s.DepthFunc)
+	}
+	gl.DepthMask(s.DepthWrite)
+
+	enable(gl.CULL_FACE, s.CullFace != 0)
+	if s.CullFace != 0 {
+		gl.CullFace(s.CullFace)
+	}
+
+	enable(gl.BLEND, s.Blend)
+	if s.Blend {
+		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	}
+
+	enable(gl.STENCIL_TEST, s.Stencil.Func != 0)
+	if s.Stencil.Func != 0 {
+		gl.StencilFunc(s.Stencil.Func, s.Stencil.Ref, 0xff)
+		pass := s.Stencil.Pass
+		if pass == 0 {
+			pass = gl.KEEP
+		}
+		gl.StencilOp(gl.KEEP, gl.KEEP, pass)
+	}
+	gl.StencilMask(s.stencilMask())
+}
+
+// stencilMask lets through writes to the stencil buffer only when the
+// state stores something
+func (s RenderState) stencilMask() uint32 {
+	if s.Stencil.Pass != 0 {
+		return 0xff
+	}
+	return 0x00
+}
+
+// Clear clears the current framebuffer to the clear color, far depth and
+// zero stencil, lifting the write masks for it as a clear only reaches
+// writable buffers
+func (s RenderState) Clear() {
+	gl.DepthMask(true)
+	gl.StencilMask(0xff)
+	gl.ClearColor(s.ClearColor[0], s.ClearColor[1], s.ClearColor[2], s.ClearColor[3])
+	gl.ClearStencil(0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT | gl.STENCIL_BUFFER_BIT)
+	gl.DepthMask(s.DepthWrite)
+	gl.StencilMask(s.stencilMask())
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"image"
+	"image/png"
+	"os"
+)
+
+// captureScreenshot saves what has been drawn to a window's back buffer
+// as a PNG. The window's context must be current, and it must be called
+// before the buffers are swapped.
+func captureScreenshot(window *glfw.Window, path string) error {
+	img := readBackBuffer(window)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// readBackBuffer copies a window's back buffer into an opaque image, top
+// row first. The window's context must be current.
+func readBackBuffer(window *glfw.Window) *image.RGBA {
+	width, height := window.GetFramebufferSize()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadBuffer(gl.BACK)
+	gl.ReadPixels(0, 0, int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+
+	// rows are read from the bottom up
+	flipRows(img)
+
+	// alpha is whatever blending left behind, so keep the image opaque
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 255
+	}
+
+	return img
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"github.com/angus-g/gopengl/geometry"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Skybox draws a cubemap behind the scene, at the far plane in every
+// direction
+type Skybox struct {
+	program *Program
+	mesh    *Mesh
+	texture *Texture
+	state   RenderState
+}
+
+// NewSkybox loads the six faces of a cubemap, in the order and color
+// space NewCubemap takes them, to draw with the given projection's depth
+// mode
+func NewSkybox(faces [6]string, srgb bool, proj *projection) (*Skybox, error) {
+	texture, err := NewCubemap(faces, srgb)
+	if err != nil {
+		return nil, err
+	}
+
+	program, err := NewProgram(assets, "skybox_vertex.glsl", "skybox_fragment.glsl")
+	if err != nil {
+		texture.Delete()
+		return nil, err
+	}
+	program.Use()
+	program.SetInt("sky", 0)
+	program.SetInt("reversed", boolToInt(proj.Depth == depthReversed))
+
+	// depth sits exactly on the far plane, which only passes the depth
+	// test against a cleared buffer if equal depths do
+	depthFunc := uint32(gl.LEQUAL)
+	if proj.Depth == depthReversed {
+		depthFunc = gl.GEQUAL
+	}
+
+	return &Skybox{
+		program: program,
+		mesh:    NewMesh(program, geometry.Cube(), nil, meshAttribs),
+		texture: texture,
+		state:   RenderState{DepthTest: true, DepthFunc: depthFunc},
+	}, nil
+}
+
+// Draw fills whatever the scene left uncovered, so it goes after the
+// opaque geometry to save shading the hidden sky
+func (s *Skybox) Draw(view, proj mgl32.Mat4) {
+	s.state.Apply()
+	s.program.Use()
+	s.program.SetMat4("view", view)
+	s.program.SetMat4("proj", proj)
+
+	s.texture.Bind(0)
+	s.mesh.Draw()
+}
+
+// Delete frees the skybox's program, mesh and cubemap
+func (s *Skybox) Delete() {
+	s.mesh.Delete()
+	s.program.Delete()
+	s.texture.Delete()
+}
@@ -0,0 +1,11 @@
+#version 150
+
+in vec3 direction;
+
+uniform samplerCube sky;
+
+out vec4 outColor;
+
+void main() {
+    outColor = texture(sky, direction);
+}
@@ -0,0 +1,22 @@
+#version 150
+
+in vec3 position;
+
+uniform mat4 view;
+uniform mat4 proj;
+
+// depth runs from 1 at the near plane to 0 at the far plane
+uniform bool reversed;
+
+out vec3 direction;
+
+void main() {
+    // cubemaps are y up and the scene is z up
+    direction = vec3(position.x, position.z, -position.y);
+
+    // only the view's rotation, so the sky never gets any closer
+    vec4 clip = proj * mat4(mat3(view)) * vec4(position, 1.0);
+
+    // pin depth to the far plane after the divide by w
+    gl_Position = reversed ? vec4(clip.xy, 0.0, clip.w) : clip.xyww;
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"math"
+)
+
+// height of the terrain's hills above and below its middle, before it is
+// scaled to the scene
+const terrainHeight = 0.15
+
+// terrainSurface is rolling hills over [-1, 1] on x and y, split into n
+// by n squares, as a floor for splatting texture array layers over by
+// height. Positions, normals and texture coordinates come in separate
+// buffers, the way many assets store them, with texture coordinates
+// repeating four times across.
+func terrainSurface(n int) ([]AttribBuffer, []uint32) {
+	height := func(x, y float64) float64 {
+		return terrainHeight * math.Sin(2.5*x) * math.Cos(3.0*y)
+	}
+
+	vertices := (n + 1) * (n + 1)
+	positions := make([]float32, 0, 3*vertices)
+	normals := make([]float32, 0, 3*vertices)
+	texCoords := make([]float32, 0, 2*vertices)
+	for i := 0; i <= n; i++ {
+		y := 2.0*float64(i)/float64(n) - 1.0
+		for j := 0; j <= n; j++ {
+			x := 2.0*float64(j)/float64(n) - 1.0
+
+			// the normal leans against the slope along each axis
+			dx := terrainHeight * 2.5 * math.Cos(2.5*x) * math.Cos(3.0*y)
+			dy := -terrainHeight * 3.0 * math.Sin(2.5*x) * math.Sin(3.0*y)
+			length := math.Sqrt(dx*dx + dy*dy + 1.0)
+
+			positions = append(positions, float32(x), float32(y), float32(height(x, y)))
+			normals = append(normals, float32(-dx/length), float32(-dy/length), float32(1.0/length))
+			texCoords = append(texCoords, 4.0*float32(j)/float32(n), 4.0*float32(i)/float32(n))
+		}
+	}
+
+	// counter-clockwise seen from above
+	corner := func(i, j int) uint32 {
+		return uint32(i*(n+1) + j)
+	}
+	var indices []uint32
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			indices = append(indices,
+				corner(i, j), corner(i, j+1), corner(i+1, j+1),
+				corner(i, j), corner(i+1, j+1), corner(i+1, j))
+		}
+	}
+
+	return []AttribBuffer{
+		{"position", 3, positions},
+		{"normal", 3, normals},
+		{"texCoord", 2, texCoords},
+	}, indices
+}
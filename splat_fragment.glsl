@@ -0,0 +1,33 @@
+#version 150
+
+in vec3 vertNorm;
+in vec3 fragPos;
+in vec2 fragTexCoord;
+
+uniform vec3 lightDir;
+uniform vec3 lightCol;
+uniform float ambient;
+
+// the -splat images, lowest ground first, blended by height between
+// heightRange.x, all the first layer, and heightRange.y, all the last
+uniform sampler2DArray layers;
+uniform int layerCount;
+uniform vec2 heightRange;
+
+out vec4 outColor;
+
+void main() {
+    float height = clamp((fragPos.z - heightRange.x) / (heightRange.y - heightRange.x), 0.0, 1.0);
+    float layer = height * float(layerCount - 1);
+
+    // fade between the two layers either side of the height
+    float below = floor(layer);
+    float above = min(below + 1.0, float(layerCount - 1));
+    vec3 base = mix(
+        texture(layers, vec3(fragTexCoord, below)).rgb,
+        texture(layers, vec3(fragTexCoord, above)).rgb,
+        layer - below);
+
+    float diffuse = max(dot(normalize(vertNorm), -normalize(lightDir)), 0.0);
+    outColor = vec4((ambient + diffuse) * base * lightCol, 1.0);
+}
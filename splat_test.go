@@ -0,0 +1,21 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestTerrainSurfaceLayout(t *testing.T) {
+	buffers, indices := terrainSurface(4)
+	count, _, err := separateLayout(buffers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 5*5 {
+		t.Errorf("terrain has %v vertices, want 25", count)
+	}
+	for _, index := range indices {
+		if int32(index) >= count {
+			t.Fatalf("terrain index %v past its %v vertices", index, count)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// floats per sprite vertex: position, texture coordinate and tint
+const spriteVertexSize = 2 + 2 + 4
+
+// sprites are drawn over everything, whichever way the quads face
+var spriteState = RenderState{Blend: true}
+
+// SpriteBatch accumulates textured quads between Begin and End and draws
+// them with as few draw calls as possible, starting a new batch only when
+// the texture changes or the buffer fills. Coordinates are in pixels with
+// the origin at the top left of the framebuffer.
+//
+// The batch owns a vertex array object, so it can only be drawn in the
+// context it was created in.
+type SpriteBatch struct {
+	program  *Program
+	vao      uint32
+	vbo      uint32
+	capacity int
+
+	vertices []float32
+	texture  *Texture
+	drawing  bool
+}
+
+// NewSpriteBatch creates a batch able to hold capacity sprites per draw
+func NewSpriteBatch(capacity int) (*SpriteBatch, error) {
+	program, err := NewProgram(assets, "sprite_vertex.glsl", "sprite_fragment.glsl")
+	if err != nil {
+		return nil, err
+	}
+
+	b := &SpriteBatch{
+		program:  program,
+		capacity: capacity,
+		vertices: make([]float32, 0, capacity*6*spriteVertexSize),
+	}
+
+	gl.GenVertexArrays(1, &b.vao)
+	gl.BindVertexArray(b.vao)
+
+	// storage is reserved up front and refilled on each flush
+	gl.GenBuffers(1, &b.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, cap(b.vertices)*4, nil, gl.DYNAMIC_DRAW)
+
+	stride := int32(spriteVertexSize * 4)
+	for _, attrib := range []struct {
+		name   string
+		size   int32
+		offset int
+	}{
+		{"position\x00", 2, 0},
+		{"texCoord\x00", 2, 2 * 4},
+		{"color\x00", 4, 4 * 4},
+	} {
+		loc := gl.GetAttribLocation(program.ID, gl.Str(attrib.name))
+		if loc < 0 {
+			b.Delete()
+			return nil, fmt.Errorf("sprite shader has no attribute %v", attrib.name)
+		}
+		gl.VertexAttribPointer(uint32(loc), attrib.size, gl.FLOAT, false, stride, gl.PtrOffset(attrib.offset))
+		gl.EnableVertexAttribArray(uint32(loc))
+	}
+
+	return b, nil
+}
+
+// Begin starts a batch drawn over a framebuffer of the given size
+func (b *SpriteBatch) Begin(width, height int) {
+	b.drawing = true
+	b.vertices = b.vertices[:0]
+
+	proj := mgl32.Ortho(0.0, float32(width), float32(height), 0.0, -1.0, 1.0)
+	b.program.Use()
+	b.program.SetMat4("proj", proj)
+}
+
+// Draw queues a quad at pos of the given size, sampling the texture over
+// uv (u0, v0, u1, v1) multiplied by the tint color
+func (b *SpriteBatch) Draw(texture *Texture, pos, size mgl32.Vec2, uv, tint mgl32.Vec4) {
+	if !b.drawing {
+		panic("SpriteBatch.Draw called outside Begin/End")
+	}
+
+	if texture != b.texture || len(b.vertices) == cap(b.vertices) {
+		b.flush()
+		b.texture = texture
+	}
+
+	x0, y0 := pos[0], pos[1]
+	x1, y1 := x0+size[0], y0+size[1]
+	u0, v0, u1, v1 := uv[0], uv[1], uv[2], uv[3]
+
+	corner := func(x, y, u, v float32) {
+		b.vertices = append(b.vertices, x, y, u, v, tint[0], tint[1], tint[2], tint[3])
+	}
+
+	// two triangles per quad
+	corner(x0, y0, u0, v0)
+	corner(x1, y0, u1, v0)
+	corner(x1, y1, u1, v1)
+	corner(x0, y0, u0, v0)
+	corner(x1, y1, u1, v1)
+	corner(x0, y1, u0, v1)
+}
+
+// End draws any queued sprites, leaving the render state for the next
+// pass to apply its own
+func (b *SpriteBatch) End() {
+	b.flush()
+	b.drawing = false
+	b.texture = nil
+}
+
+// flush draws and empties the queued sprites
+func (b *SpriteBatch) flush() {
+	if len(b.vertices) == 0 {
+		return
+	}
+
+	spriteState.Apply()
+
+	b.texture.Bind(0)
+
+	// orphan the previous contents so the driver need not wait on them
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, cap(b.vertices)*4, nil, gl.DYNAMIC_DRAW)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(b.vertices)*4, gl.Ptr(b.vertices))
+
+	gl.BindVertexArray(b.vao)
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(len(b.vertices)/spriteVertexSize))
+	capture.logf("sprite batch: program %v, texture %v, %v sprites", b.program.ID, b.texture.ID, len(b.vertices)/spriteVertexSize/6)
+
+	b.vertices = b.vertices[:0]
+}
+
+// Delete frees the batch's GL objects, zeroing them so that a second
+// Delete panics
+func (b *SpriteBatch) Delete() {
+	if b.vbo == 0 {
+		panic("SpriteBatch.Delete called twice")
+	}
+	gl.DeleteBuffers(1, &b.vbo)
+	gl.DeleteVertexArrays(1, &b.vao)
+	b.vbo, b.vao = 0, 0
+	b.program.Delete()
+}
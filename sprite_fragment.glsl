@@ -0,0 +1,12 @@
+#version 150
+
+in vec2 fragTexCoord;
+in vec4 fragColor;
+
+uniform sampler2D tex;
+
+out vec4 outColor;
+
+void main() {
+    outColor = fragColor * texture(tex, fragTexCoord);
+}
@@ -0,0 +1,16 @@
+#version 150
+
+in vec2 position;
+in vec2 texCoord;
+in vec4 color;
+
+uniform mat4 proj;
+
+out vec2 fragTexCoord;
+out vec4 fragColor;
+
+void main() {
+    gl_Position = proj * vec4(position, 0.0, 1.0);
+    fragTexCoord = texCoord;
+    fragColor = color;
+}
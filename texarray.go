@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"image"
+	"image/draw"
+)
+
+// LoadTextureArray uploads same-sized images as the layers of one
+// GL_TEXTURE_2D_ARRAY, sampled in shaders with a sampler2DArray and the
+// layer index as the third texture coordinate
+func LoadTextureArray(paths []string, opts TextureOptions) (*Texture, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("texture array needs at least one layer")
+	}
+
+	layers := make([]*image.RGBA, len(paths))
+	for i, path := range paths {
+		img, err := loadImage(diskFS{}, path)
+		if err != nil {
+			return nil, err
+		}
+
+		rgba := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+		draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+		if first := layers[0]; first != nil && rgba.Rect.Size() != first.Rect.Size() {
+			return nil, fmt.Errorf("layer %v is %v but %v is %v, layers must match",
+				path, rgba.Rect.Size(), paths[0], first.Rect.Size())
+		}
+		if !opts.NoFlip {
+			flipRows(rgba)
+		}
+		layers[i] = rgba
+	}
+
+	size := layers[0].Rect.Size()
+	t := &Texture{Width: size.X, Height: size.Y, target: gl.TEXTURE_2D_ARRAY, opts: opts}
+	gl.GenTextures(1, &t.ID)
+	gl.BindTexture(t.target, t.ID)
+	opts.apply(t.target)
+
+	// allocate every layer, then fill them one at a time
+	gl.TexImage3D(t.target, 0, opts.internalFormat(),
+		int32(size.X), int32(size.Y), int32(len(layers)),
+		0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	for i, rgba := range layers {
+		gl.TexSubImage3D(t.target, 0, 0, 0, int32(i),
+			int32(size.X), int32(size.Y), 1,
+			gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
+	}
+	if opts.Mipmaps {
+		gl.GenerateMipmap(t.target)
+	}
+
+	return t, nil
+}
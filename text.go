@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// a font atlas holds printable ASCII from the space onwards in rows of
+// fontColumns equal cells, top left first
+const (
+	fontColumns = 16
+	fontRows    = 6
+	fontFirst   = ' '
+	fontLast    = '~'
+)
+
+// TextRenderer draws lines of monospaced text from a bitmap font atlas
+// through a sprite batch, with the same pixel coordinates and blending.
+// Text drawn after the scene lands on top of it, as sprites ignore depth.
+type TextRenderer struct {
+	Color mgl32.Vec4
+
+	batch *SpriteBatch
+	font  *Texture
+
+	// a glyph's size in pixels, and in texture coordinates
+	cell   mgl32.Vec2
+	cellUV mgl32.Vec2
+}
+
+// NewTextRenderer loads a font atlas image of 16 by 6 glyphs, drawing
+// them in white until Color is changed
+func NewTextRenderer(atlas string) (*TextRenderer, error) {
+	font, err := NewTexture(diskFS{}, atlas, TextureOptions{
+		MinFilter: gl.NEAREST,
+		MagFilter: gl.NEAREST,
+		WrapS:     gl.CLAMP_TO_EDGE,
+		WrapT:     gl.CLAMP_TO_EDGE,
+		NoFlip:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if font.Width%fontColumns != 0 || font.Height%fontRows != 0 {
+		font.Delete()
+		return nil, fmt.Errorf("font atlas %v is %vx%v, not %v by %v equal cells",
+			atlas, font.Width, font.Height, fontColumns, fontRows)
+	}
+
+	batch, err := NewSpriteBatch(256)
+	if err != nil {
+		font.Delete()
+		return nil, err
+	}
+
+	return &TextRenderer{
+		Color:  mgl32.Vec4{1.0, 1.0, 1.0, 1.0},
+		batch:  batch,
+		font:   font,
+		cell:   mgl32.Vec2{float32(font.Width / fontColumns), float32(font.Height / fontRows)},
+		cellUV: mgl32.Vec2{1.0 / fontColumns, 1.0 / fontRows},
+	}, nil
+}
+
+// Begin starts text drawn over a framebuffer of the given size
+func (r *TextRenderer) Begin(width, height int) {
+	r.batch.Begin(width, height)
+}
+
+// DrawText queues s with its top left corner at x, y in pixels and
+// glyphs scale times their size in the atlas. Newlines start a new line
+// below, and characters missing from the atlas are drawn as '?'.
+func (r *TextRenderer) DrawText(s string, x, y float32, scale float32) {
+	size := r.cell.Mul(scale)
+	pos := mgl32.Vec2{x, y}
+	for _, c := range s {
+		if c == '\n' {
+			pos = mgl32.Vec2{x, pos[1] + size[1]}
+			continue
+		}
+		if c < fontFirst || c > fontLast {
+			c = '?'
+		}
+
+		if c != ' ' {
+			i := int(c - fontFirst)
+			u := float32(i%fontColumns) * r.cellUV[0]
+			v := float32(i/fontColumns) * r.cellUV[1]
+			r.batch.Draw(r.font, pos, size, mgl32.Vec4{u, v, u + r.cellUV[0], v + r.cellUV[1]}, r.Color)
+		}
+		pos[0] += size[0]
+	}
+}
+
+// LineHeight is the distance in pixels between lines at scale
+func (r *TextRenderer) LineHeight(scale float32) float32 {
+	return r.cell[1] * scale
+}
+
+// End draws the queued text
+func (r *TextRenderer) End() {
+	r.batch.End()
+}
+
+// Delete frees the batch and the font atlas
+func (r *TextRenderer) Delete() {
+	r.batch.Delete()
+	r.font.Delete()
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"image"
+	"image/draw"
+	"io/fs"
+)
+
+// TextureOptions control how a texture is sampled. Zero filters are
+// linear, trilinear when minifying a mipmapped texture, and zero wrap
+// modes repeat.
+type TextureOptions struct {
+	MinFilter int32
+	MagFilter int32
+	WrapS     int32
+	WrapT     int32
+	Mipmaps   bool
+
+	// images are stored top row first but texture coordinates start at
+	// the bottom, so rows are flipped on upload unless this is set, as for
+	// 2D drawing with a top left origin
+	NoFlip bool
+
+	// the image holds sRGB colors, stored as GL_SRGB8_ALPHA8 so that
+	// sampling decodes them to linear for lighting in an sRGB framebuffer.
+	// This is what makes -srgb look right, and the one place textures
+	// differ with it. Leave it off for data that is not color, like
+	// normal maps, which must read back exactly as stored.
+	SRGB bool
+}
+
+// internalFormat is how the texture stores RGBA images
+func (o TextureOptions) internalFormat() int32 {
+	if o.SRGB {
+		return gl.SRGB8_ALPHA8
+	}
+	return gl.RGBA8
+}
+
+// apply sets the sampling parameters of the texture bound to target
+func (o TextureOptions) apply(target uint32) {
+	minFilter, magFilter := o.MinFilter, o.MagFilter
+	if minFilter == 0 {
+		minFilter = gl.LINEAR
+		if o.Mipmaps {
+			minFilter = gl.LINEAR_MIPMAP_LINEAR
+		}
+	}
+	if magFilter == 0 {
+		magFilter = gl.LINEAR
+	}
+
+	wrapS, wrapT := o.WrapS, o.WrapT
+	if wrapS == 0 {
+		wrapS = gl.REPEAT
+	}
+	if wrapT == 0 {
+		wrapT = gl.REPEAT
+	}
+
+	gl.TexParameteri(target, gl.TEXTURE_MIN_FILTER, minFilter)
+	gl.TexParameteri(target, gl.TEXTURE_MAG_FILTER, magFilter)
+	gl.TexParameteri(target, gl.TEXTURE_WRAP_S, wrapS)
+	gl.TexParameteri(target, gl.TEXTURE_WRAP_T, wrapT)
+}
+
+// Texture is an image uploaded to the GPU
+type Texture struct {
+	ID     uint32
+	Width  int
+	Height int
+
+	target uint32
+	opts   TextureOptions
+}
+
+// NewTexture decodes an image file into a 2D texture
+func NewTexture(fsys fs.FS, file string, opts TextureOptions) (*Texture, error) {
+	img, err := loadImage(fsys, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTextureFromImage(img, opts)
+}
+
+// NewTextureFromImage uploads an already decoded image
+func NewTextureFromImage(img image.Image, opts TextureOptions) (*Texture, error) {
+	t := &Texture{target: gl.TEXTURE_2D, opts: opts}
+	gl.GenTextures(1, &t.ID)
+	gl.BindTexture(t.target, t.ID)
+	opts.apply(t.target)
+
+	if err := t.upload(img); err != nil {
+		t.Delete()
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Reload decodes an image file again into the texture, keeping its
+// handle so that anything bound to it stays valid
+func (t *Texture) Reload(fsys fs.FS, file string) error {
+	if t.target != gl.TEXTURE_2D {
+		return fmt.Errorf("only 2D textures can be reloaded")
+	}
+
+	img, err := loadImage(fsys, file)
+	if err != nil {
+		return err
+	}
+
+	gl.BindTexture(t.target, t.ID)
+	return t.upload(img)
+}
+
+// upload replaces the contents of the texture, which must be bound
+func (t *Texture) upload(img image.Image) error {
+	size := img.Bounds().Size()
+	rgba := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	if rgba.Stride != size.X*4 {
+		return fmt.Errorf("cannot upload %vx%v image: RGBA copy has a stride of %v bytes, need tightly packed rows of %v",
+			size.X, size.Y, rgba.Stride, size.X*4)
+	}
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	if !t.opts.NoFlip {
+		flipRows(rgba)
+	}
+
+	gl.TexImage2D(t.target, 0, t.opts.internalFormat(),
+		int32(size.X), int32(size.Y),
+		0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
+	if t.opts.Mipmaps {
+		gl.GenerateMipmap(t.target)
+	}
+	t.Width, t.Height = size.X, size.Y
+
+	return nil
+}
+
+// Bind binds the texture to a texture unit, counting from 0
+func (t *Texture) Bind(unit uint32) {
+	if t.ID == 0 {
+		panic("Texture.Bind called after Delete")
+	}
+	gl.ActiveTexture(gl.TEXTURE0 + unit)
+	gl.BindTexture(t.target, t.ID)
+	capture.logf("bind texture %v to unit %v", t.ID, unit)
+}
+
+// Delete frees the texture, zeroing its handle so that a second Delete
+// panics
+func (t *Texture) Delete() {
+	if t.ID == 0 {
+		panic("Texture.Delete called twice")
+	}
+	gl.DeleteTextures(1, &t.ID)
+	t.ID = 0
+}
+
+// flipRows turns an image upside down in place
+func flipRows(rgba *image.RGBA) {
+	row := make([]uint8, rgba.Stride)
+	for top, bottom := 0, rgba.Rect.Dy()-1; top < bottom; top, bottom = top+1, bottom-1 {
+		a := rgba.Pix[top*rgba.Stride : (top+1)*rgba.Stride]
+		b := rgba.Pix[bottom*rgba.Stride : (bottom+1)*rgba.Stride]
+		copy(row, a)
+		copy(a, b)
+		copy(b, row)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+)
+
+// sides of the circular cross-section of generated tubes
+const tubeSides = 12
+
+// catmullRom interpolates the span from p1 to p2 at t in [0, 1]
+func catmullRom(p0, p1, p2, p3 mgl32.Vec3, t float32) mgl32.Vec3 {
+	t2 := t * t
+	t3 := t2 * t
+
+	return p1.Mul(2.0).
+		Add(p2.Sub(p0).Mul(t)).
+		Add(p0.Mul(2.0).Sub(p1.Mul(5.0)).Add(p2.Mul(4.0)).Sub(p3).Mul(t2)).
+		Add(p1.Mul(3.0).Sub(p0).Sub(p2.Mul(3.0)).Add(p3).Mul(t3)).
+		Mul(0.5)
+}
+
+// GenTubeAlongSpline uploads a tube along a spline through points as a
+// lit mesh drawn with program, or returns nil if there are too few points
+// to make one
+func GenTubeAlongSpline(points []mgl32.Vec3, radius float32, segments int, program *Program) *Mesh {
+	m := tubeAlongSpline(points, radius, segments)
+	if len(m.indices) == 0 {
+		return nil
+	}
+
+	return NewMesh(program, m.vertices, m.indices, meshAttribs)
+}
+
+// tubeAlongSpline extrudes a circle of the given radius along a
+// Catmull-Rom spline through points, sampling segments steps between each
+// pair of points. The curve passes through every point, and the result is
+// indexed triangles with smooth normals, like a loaded model, with u
+// running around the tube and v along it.
+func tubeAlongSpline(points []mgl32.Vec3, radius float32, segments int) *modelData {
+	if len(points) < 2 || segments < 1 {
+		return &modelData{}
+	}
+
+	// sample the curve, repeating the end points as their own neighbours
+	at := func(i int) mgl32.Vec3 {
+		if i < 0 {
+			i = 0
+		} else if i >= len(points) {
+			i = len(points) - 1
+		}
+		return points[i]
+	}
+
+	var centers []mgl32.Vec3
+	for i := 0; i < len(points)-1; i++ {
+		for s := 0; s < segments; s++ {
+			t := float32(s) / float32(segments)
+			centers = append(centers, catmullRom(at(i-1), at(i), at(i+1), at(i+2), t))
+		}
+	}
+	centers = append(centers, points[len(points)-1])
+
+	tangent := func(i int) mgl32.Vec3 {
+		a, b := centers[i], centers[i]
+		if i > 0 {
+			a = centers[i-1]
+		}
+		if i < len(centers)-1 {
+			b = centers[i+1]
+		}
+		if d := b.Sub(a); d.Len() > 0.0 {
+			return d.Normalize()
+		}
+		return mgl32.Vec3{0.0, 0.0, 1.0}
+	}
+
+	// parallel transport a normal along the curve so that the rings do
+	// not twist, starting from any direction perpendicular to it
+	t0 := tangent(0)
+	normal := t0.Cross(mgl32.Vec3{0.0, 0.0, 1.0})
+	if normal.Len() < 1e-3 {
+		normal = t0.Cross(mgl32.Vec3{1.0, 0.0, 0.0})
+	}
+	normal = normal.Normalize()
+
+	rings := make([][tubeSides]mgl32.Vec3, len(centers))
+	prev := t0
+	for i := range centers {
+		t := tangent(i)
+		normal = mgl32.QuatBetweenVectors(prev, t).Rotate(normal)
+		binormal := t.Cross(normal).Normalize()
+		prev = t
+
+		for j := 0; j < tubeSides; j++ {
+			angle := 2.0 * math.Pi * float64(j) / tubeSides
+			rings[i][j] = normal.Mul(float32(math.Cos(angle))).Add(binormal.Mul(float32(math.Sin(angle))))
+		}
+	}
+
+	// each ring repeats its first vertex to close the seam with u = 1
+	m := &modelData{}
+	for i := range centers {
+		for j := 0; j <= tubeSides; j++ {
+			dir := rings[i][j%tubeSides]
+			pos := centers[i].Add(dir.Mul(radius))
+			u := float32(j) / tubeSides
+			v := float32(i) / float32(len(centers)-1)
+			m.vertices = append(m.vertices, pos[0], pos[1], pos[2], dir[0], dir[1], dir[2], u, v)
+		}
+	}
+
+	corner := func(i, j int) uint32 {
+		return uint32(i*(tubeSides+1) + j)
+	}
+
+	// two triangles for each side between consecutive rings, wound
+	// counter-clockwise seen from outside
+	for i := 0; i < len(centers)-1; i++ {
+		for j := 0; j < tubeSides; j++ {
+			m.indices = append(m.indices,
+				corner(i, j), corner(i+1, j+1), corner(i+1, j),
+				corner(i, j), corner(i, j+1), corner(i+1, j+1))
+		}
+	}
+
+	return m
+}
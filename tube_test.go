@@ -0,0 +1,72 @@
+package main
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"testing"
+)
+
+func TestTubeAlongSpline(t *testing.T) {
+	points := []mgl32.Vec3{
+		{0.0, 0.0, 0.0},
+		{1.0, 0.0, 0.0},
+		{1.0, 1.0, 0.5},
+		{0.0, 2.0, 1.0},
+	}
+	const radius, segments = 0.1, 5
+	m := tubeAlongSpline(points, radius, segments)
+
+	// a ring per segment and one to finish, each repeating its first
+	// vertex, and two triangles per side between rings
+	rings := (len(points)-1)*segments + 1
+	if n, want := len(m.vertices)/meshVertexSize, rings*(tubeSides+1); n != want {
+		t.Fatalf("tube has %v vertices, want %v", n, want)
+	}
+	if n, want := len(m.indices), (rings-1)*tubeSides*6; n != want {
+		t.Errorf("tube has %v indices, want %v", n, want)
+	}
+	for _, index := range m.indices {
+		if int(index) >= rings*(tubeSides+1) {
+			t.Fatalf("tube index %v past its vertices", index)
+		}
+	}
+
+	vertex := func(ring, side int) (pos, normal mgl32.Vec3) {
+		v := m.vertices[(ring*(tubeSides+1)+side)*meshVertexSize:]
+		return mgl32.Vec3{v[0], v[1], v[2]}, mgl32.Vec3{v[3], v[4], v[5]}
+	}
+
+	for ring := 0; ring < rings; ring++ {
+		// the evenly spaced sides of a ring average to its center
+		var center mgl32.Vec3
+		for side := 0; side < tubeSides; side++ {
+			pos, _ := vertex(ring, side)
+			center = center.Add(pos.Mul(1.0 / tubeSides))
+		}
+		if ring%segments == 0 {
+			if point := points[ring/segments]; center.Sub(point).Len() > 1e-5 {
+				t.Errorf("ring %v is centered on %v, want control point %v", ring, center, point)
+			}
+		}
+
+		// each normal is unit length and points from the center out to
+		// its vertex
+		for side := 0; side <= tubeSides; side++ {
+			pos, normal := vertex(ring, side)
+			if math.Abs(float64(normal.Len())-1.0) > 1e-5 {
+				t.Errorf("ring %v side %v has normal %v of length %v", ring, side, normal, normal.Len())
+			}
+			if out := pos.Sub(center.Add(normal.Mul(radius))); out.Len() > 1e-5 {
+				t.Errorf("ring %v side %v at %v is not radius along its normal %v from the center %v", ring, side, pos, normal, center)
+			}
+		}
+	}
+
+	// too few points or segments make nothing
+	if m := tubeAlongSpline(points[:1], radius, segments); len(m.vertices) != 0 || len(m.indices) != 0 {
+		t.Errorf("tube through one point has %v floats and %v indices, want none", len(m.vertices), len(m.indices))
+	}
+	if m := tubeAlongSpline(points, radius, 0); len(m.vertices) != 0 || len(m.indices) != 0 {
+		t.Errorf("tube of no segments has %v floats and %v indices, want none", len(m.vertices), len(m.indices))
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"strings"
+)
+
+// activeUniforms introspects the uniforms that survived linking, keyed by
+// name. Arrays are listed once under their base name.
+func activeUniforms(program uint32) map[string]int32 {
+	var count, maxLength int32
+	gl.GetProgramiv(program, gl.ACTIVE_UNIFORMS, &count)
+	gl.GetProgramiv(program, gl.ACTIVE_UNIFORM_MAX_LENGTH, &maxLength)
+
+	uniforms := make(map[string]int32, count)
+	for i := uint32(0); i < uint32(count); i++ {
+		var length, size int32
+		var xtype uint32
+		name := make([]uint8, maxLength+1)
+		gl.GetActiveUniform(program, i, maxLength, &length, &size, &xtype, &name[0])
+
+		n := strings.TrimSuffix(string(name[:length]), "[0]")
+		uniforms[n] = gl.GetUniformLocation(program, gl.Str(n+"\x00"))
+	}
+
+	return uniforms
+}
+
+// standardUniforms are the well-known values bound automatically on any
+// program that declares them
+type standardUniforms struct {
+	Model      mgl32.Mat4
+	View       mgl32.Mat4
+	Proj       mgl32.Mat4
+	Time       float32
+	Resolution mgl32.Vec2
+	CameraPos  mgl32.Vec3
+	AudioLevel float32
+}
+
+// autoBinder remembers which standard uniforms a program declares
+type autoBinder struct {
+	locations map[string]int32
+}
+
+func newAutoBinder(program *Program) *autoBinder {
+	return &autoBinder{locations: activeUniforms(program.ID)}
+}
+
+// apply uploads the standard uniforms to the currently bound program,
+// skipping any it does not declare
+func (b *autoBinder) apply(u *standardUniforms) {
+	if loc, ok := b.locations["model"]; ok {
+		gl.UniformMatrix4fv(loc, 1, false, &u.Model[0])
+		capture.logf("uniform model = %v", u.Model)
+	}
+	if loc, ok := b.locations["view"]; ok {
+		gl.UniformMatrix4fv(loc, 1, false, &u.View[0])
+		capture.logf("uniform view = %v", u.View)
+	}
+	if loc, ok := b.locations["proj"]; ok {
+		gl.UniformMatrix4fv(loc, 1, false, &u.Proj[0])
+		capture.logf("uniform proj = %v", u.Proj)
+	}
+	if loc, ok := b.locations["time"]; ok {
+		gl.Uniform1f(loc, u.Time)
+		capture.logf("uniform time = %v", u.Time)
+	}
+	if loc, ok := b.locations["resolution"]; ok {
+		gl.Uniform2fv(loc, 1, &u.Resolution[0])
+		capture.logf("uniform resolution = %v", u.Resolution)
+	}
+	if loc, ok := b.locations["cameraPos"]; ok {
+		gl.Uniform3fv(loc, 1, &u.CameraPos[0])
+		capture.logf("uniform cameraPos = %v", u.CameraPos)
+	}
+	if loc, ok := b.locations["audioLevel"]; ok {
+		gl.Uniform1f(loc, u.AudioLevel)
+		capture.logf("uniform audioLevel = %v", u.AudioLevel)
+	}
+}
+
+// boolToInt converts a flag for a bool uniform
+func boolToInt(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
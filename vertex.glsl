@@ -2,14 +2,30 @@
 
 in vec3 position;
 in vec3 normal;
+in vec2 texCoord;
 
 uniform mat4 model;
 uniform mat4 view;
 uniform mat4 proj;
 
+// logarithmic depth for large scenes without clip control
+uniform bool logDepth;
+uniform float farPlane;
+
 out vec3 vertNorm;
+out vec3 fragPos;
+out vec2 fragTexCoord;
 
 void main() {
     gl_Position = proj * view * model * vec4(position, 1.0);
-    vertNorm = (model * vec4(normal, 1.0)).xyz;
+    if (logDepth) {
+        // spread depth precision evenly over orders of magnitude, at the
+        // cost of slight errors inside triangles close to the camera
+        float fcoef = 2.0 / log2(farPlane + 1.0);
+        gl_Position.z = (log2(max(1e-6, 1.0 + gl_Position.w)) * fcoef - 1.0) * gl_Position.w;
+    }
+    // normals turn with the model but must not be translated
+    vertNorm = mat3(model) * normal;
+    fragPos = (model * vec4(position, 1.0)).xyz;
+    fragTexCoord = texCoord;
 }
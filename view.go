@@ -0,0 +1,76 @@
+package main
+
+import (
+	"github.com/angus-g/gopengl/math3d"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+)
+
+// view is a window onto the scene, rendered from its own eye position.
+// All windows share one context's buffers and programs. Views must only
+// be created and drawn from the main thread.
+type view struct {
+	window *glfw.Window
+	title  string
+	eye    mgl32.Vec3
+	target mgl32.Vec3
+
+	// framebuffer size in pixels, which differs from the window size on
+	// high-DPI displays
+	width, height int
+}
+
+// newView views the scene through an open window
+func newView(window *glfw.Window, title string, eye mgl32.Vec3) *view {
+	v := &view{window: window, title: title, eye: eye}
+	v.width, v.height = window.GetFramebufferSize()
+
+	// the viewport is applied when the view is next drawn, as the event
+	// may arrive while another window's context is current
+	InputFor(window).RegisterResizeHandler(func(width, height int) bool {
+		v.width, v.height = width, height
+		return false
+	})
+
+	return v
+}
+
+// resolution is the framebuffer size as a vector
+func (v *view) resolution() mgl32.Vec2 {
+	return mgl32.Vec2{float32(v.width), float32(v.height)}
+}
+
+// aspect is the framebuffer's width to height ratio
+func (v *view) aspect() float32 {
+	if v.height == 0 {
+		// minimised windows report an empty framebuffer
+		return 1.0
+	}
+	return float32(v.width) / float32(v.height)
+}
+
+// viewMatrix looks from the eye towards the target with z up
+func (v *view) viewMatrix() mgl32.Mat4 {
+	return math3d.LookAt(v.eye, v.target)
+}
+
+// frame moves the eye along its current line of sight until a sphere
+// fills the vertical field of view (in degrees)
+func (v *view) frame(center mgl32.Vec3, radius, fov float32) {
+	dir := v.eye.Sub(v.target)
+	if dir.Len() == 0.0 {
+		dir = mgl32.Vec3{1.0, 1.0, 1.0}
+	}
+
+	distance := radius / float32(math.Sin(float64(mgl32.DegToRad(fov))/2.0))
+	v.target = center
+	v.eye = center.Add(dir.Normalize().Mul(distance))
+}
+
+// destroy closes the window, along with any vertex array objects in its
+// context
+func (v *view) destroy() {
+	forgetInput(v.window)
+	v.window.Destroy()
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// fileWatcher polls files for changes by modification time. Polling is
+// rate limited so that it can be called every frame.
+type fileWatcher struct {
+	files     []string
+	modTimes  map[string]time.Time
+	interval  time.Duration
+	lastCheck time.Time
+}
+
+// newFileWatcher watches files, checking at most once per interval
+func newFileWatcher(interval time.Duration, files ...string) *fileWatcher {
+	w := &fileWatcher{
+		files:     files,
+		modTimes:  make(map[string]time.Time, len(files)),
+		interval:  interval,
+		lastCheck: time.Now(),
+	}
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			w.modTimes[file] = info.ModTime()
+		}
+	}
+
+	return w
+}
+
+// changed reports whether any file was modified since the last check.
+// Files that cannot be read, for instance mid-save, count as unchanged.
+func (w *fileWatcher) changed() bool {
+	if time.Since(w.lastCheck) < w.interval {
+		return false
+	}
+	w.lastCheck = time.Now()
+
+	changed := false
+	for _, file := range w.files {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().Equal(w.modTimes[file]) {
+			w.modTimes[file] = info.ModTime()
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// textureWatch reloads a texture in place whenever its file changes
+type textureWatch struct {
+	texture *Texture
+	file    string
+	watch   *fileWatcher
+}
+
+// watchTexture watches the file a texture was loaded from
+func watchTexture(texture *Texture, file string) *textureWatch {
+	return &textureWatch{texture: texture, file: file, watch: newFileWatcher(time.Second, file)}
+}
+
+// reloadTexture reloads the texture if its file has changed since the
+// last check, reporting how it went. The texture's context must be
+// current.
+func (w *textureWatch) reloadTexture() {
+	if !w.watch.changed() {
+		return
+	}
+
+	if err := w.texture.Reload(diskFS{}, w.file); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to reload %v: %v\n", w.file, err)
+	} else {
+		fmt.Fprintf(os.Stderr, "reloaded %v\n", w.file)
+	}
+}
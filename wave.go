@@ -0,0 +1,47 @@
+package main
+
+import (
+	"math"
+)
+
+// waveSurface is a sheet over [-1, 1] on x and y, split into n by n
+// squares, rippled in z by waves travelling along x at time t seconds.
+// Its indices depend only on n, so later times can be uploaded over its
+// vertices.
+func waveSurface(n int, t float32) *modelData {
+	const amplitude, wavelength, speed = 0.1, 1.0, 0.5
+
+	k := 2.0 * math.Pi / wavelength
+	m := &modelData{}
+	for i := 0; i <= n; i++ {
+		y := 2.0*float64(i)/float64(n) - 1.0
+		for j := 0; j <= n; j++ {
+			x := 2.0*float64(j)/float64(n) - 1.0
+
+			// z and its slope along x, which the normal leans against
+			phase := k * (x - speed*float64(t))
+			z := amplitude * math.Sin(phase)
+			slope := amplitude * k * math.Cos(phase)
+			length := math.Sqrt(slope*slope + 1.0)
+
+			m.vertices = append(m.vertices,
+				float32(x), float32(y), float32(z),
+				float32(-slope/length), 0.0, float32(1.0/length),
+				float32(j)/float32(n), float32(i)/float32(n))
+		}
+	}
+
+	// counter-clockwise seen from above
+	corner := func(i, j int) uint32 {
+		return uint32(i*(n+1) + j)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			m.indices = append(m.indices,
+				corner(i, j), corner(i, j+1), corner(i+1, j+1),
+				corner(i, j), corner(i+1, j+1), corner(i+1, j))
+		}
+	}
+
+	return m
+}
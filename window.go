@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"os"
+)
+
+// contextVersions are tried in order until one can be created. Shaders
+// are GLSL 1.50, so nothing older than 3.2 can run them, and a 3.2
+// context only loads where the driver also offers the few 3.3 functions.
+var contextVersions = [][2]int{{3, 3}, {3, 2}}
+
+// WindowOptions pick the capabilities of a window's framebuffer and
+// context
+type WindowOptions struct {
+	// samples per pixel of multisampling, or none if 0
+	Samples int
+
+	// ask for a debug context, which may report more through KHR_debug
+	Debug bool
+
+	// encode what is drawn from linear to sRGB, so lighting and blending
+	// that happen in linear space come out at the right brightness
+	SRGB bool
+
+	// never show the window, for drawing only offscreen
+	Hidden bool
+}
+
+// Window owns GLFW for as long as it is open, along with a window whose
+// OpenGL core context is made current and loaded on creation
+type Window struct {
+	Handle *glfw.Window
+
+	// context version asked for, which the driver may exceed
+	Major, Minor int
+
+	// multisampling granted by the context, which may be less than asked
+	Samples int
+
+	// windowed placement to restore when leaving fullscreen
+	x, y, width, height int
+}
+
+// NewWindow initialises GLFW and OpenGL around a new window. GLFW must
+// only be used from the main thread, so this must be called from the
+// main goroutine, which init locks to it.
+func NewWindow(width, height int, title string, opts WindowOptions) (*Window, error) {
+	// initialize GLFW
+	if err := glfw.Init(); err != nil {
+		return nil, fmt.Errorf("failed to initialise GLFW: %v", err)
+	}
+
+	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
+	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+	glfw.WindowHint(glfw.StencilBits, 8)
+	if opts.Samples > 0 {
+		glfw.WindowHint(glfw.Samples, opts.Samples)
+	}
+	if opts.Debug {
+		glfw.WindowHint(glfw.OpenGLDebugContext, glfw.True)
+	}
+	if opts.SRGB {
+		glfw.WindowHint(glfw.SRGBCapable, glfw.True)
+	}
+	if opts.Hidden {
+		glfw.WindowHint(glfw.Visible, glfw.False)
+	}
+
+	// newest first, leaving the hints of the version that worked for any
+	// window created after this one
+	w := &Window{}
+	var err error
+	for _, version := range contextVersions {
+		glfw.WindowHint(glfw.ContextVersionMajor, version[0])
+		glfw.WindowHint(glfw.ContextVersionMinor, version[1])
+		w.Major, w.Minor = version[0], version[1]
+		if w.Handle, err = glfw.CreateWindow(width, height, title, nil, nil); err == nil {
+			break
+		}
+		fmt.Fprintf(os.Stderr, "no OpenGL %v.%v core context: %v\n", version[0], version[1], err)
+	}
+	if err != nil {
+		glfw.Terminate()
+		return nil, fmt.Errorf("failed to create window: %v", err)
+	}
+	w.Handle.MakeContextCurrent()
+
+	// initialise OpenGL library
+	if err := gl.Init(); err != nil {
+		w.Handle.Destroy()
+		glfw.Terminate()
+		return nil, fmt.Errorf("failed to initialise OpenGL %v.%v: %v", w.Major, w.Minor, err)
+	}
+
+	fmt.Fprintf(os.Stderr, "OpenGL %v on %v, GLSL %v\n",
+		gl.GoStr(gl.GetString(gl.VERSION)),
+		gl.GoStr(gl.GetString(gl.RENDERER)),
+		gl.GoStr(gl.GetString(gl.SHADING_LANGUAGE_VERSION)))
+
+	if opts.SRGB {
+		gl.Enable(gl.FRAMEBUFFER_SRGB)
+	}
+	if opts.Samples > 0 {
+		gl.Enable(gl.MULTISAMPLE)
+
+		var granted int32
+		gl.GetIntegerv(gl.SAMPLES, &granted)
+		w.Samples = int(granted)
+	}
+
+	return w, nil
+}
+
+// ShouldClose reports whether the user has asked to close the window
+func (w *Window) ShouldClose() bool {
+	return w.Handle.ShouldClose()
+}
+
+// SwapBuffers presents the frame drawn to the window
+func (w *Window) SwapBuffers() {
+	w.Handle.SwapBuffers()
+}
+
+// Fullscreen reports whether the window covers a monitor
+func (w *Window) Fullscreen() bool {
+	return w.Handle.GetMonitor() != nil
+}
+
+// ToggleFullscreen moves the window onto the primary monitor at the
+// monitor's current video mode, which most platforms treat as borderless
+// without a mode switch, or back to where it was before. The framebuffer
+// size callback follows with the new resolution.
+func (w *Window) ToggleFullscreen() {
+	if w.Fullscreen() {
+		w.Handle.SetMonitor(nil, w.x, w.y, w.width, w.height, 0)
+		return
+	}
+
+	monitor := glfw.GetPrimaryMonitor()
+	if monitor == nil {
+		return
+	}
+	w.x, w.y = w.Handle.GetPos()
+	w.width, w.height = w.Handle.GetSize()
+
+	mode := monitor.GetVideoMode()
+	w.Handle.SetMonitor(monitor, 0, 0, mode.Width, mode.Height, mode.RefreshRate)
+}
+
+// PollEvents processes pending events for every window
+func (w *Window) PollEvents() {
+	glfw.PollEvents()
+}
+
+// Destroy closes the window and shuts down GLFW, so a new Window can be
+// opened afterwards
+func (w *Window) Destroy() {
+	forgetInput(w.Handle)
+	w.Handle.Destroy()
+	glfw.Terminate()
+}